@@ -1,15 +1,23 @@
 package app
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"sort"
 	"strconv"
 	"strings"
+	"syscall"
+	"text/template"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/codegangsta/cli"
+	"github.com/docker/docker/pkg/term"
 	"github.com/docker/libcompose/project"
+	"github.com/docker/libcompose/utils"
+	shlex "github.com/flynn/go-shlex"
 )
 
 // ProjectAction is an adapter to allow the use of ordinary functions as libcompose actions.
@@ -44,8 +52,16 @@ func WithProject(factory ProjectFactory, action ProjectAction) func(context *cli
 
 // ProjectPs lists the containers.
 func ProjectPs(p *project.Project, c *cli.Context) {
-	allInfo := project.InfoSet{}
+	warnOrphans(p, "you can run down with the --remove-orphans flag to clean it up")
+
+	names := []string{}
 	for name := range p.Configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	allInfo := project.InfoSet{}
+	for _, name := range names {
 		service, err := p.CreateService(name)
 		if err != nil {
 			logrus.Fatal(err)
@@ -59,7 +75,43 @@ func ProjectPs(p *project.Project, c *cli.Context) {
 		allInfo = append(allInfo, info...)
 	}
 
-	os.Stdout.WriteString(allInfo.String())
+	filters := []string{}
+	for _, filter := range c.StringSlice("filter") {
+		filters = append(filters, strings.Split(filter, ",")...)
+	}
+
+	allInfo, err := allInfo.Filter(filters...)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	if c.Bool("q") {
+		for _, info := range allInfo {
+			fmt.Println(info.Id)
+		}
+		return
+	}
+
+	switch format := c.String("format"); format {
+	case "table":
+		os.Stdout.WriteString(allInfo.String())
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		if err := encoder.Encode(allInfo); err != nil {
+			logrus.Fatal(err)
+		}
+	default:
+		tmpl, err := template.New("ps").Parse(format)
+		if err != nil {
+			logrus.Fatalf("Invalid format: %v", err)
+		}
+		for _, info := range allInfo {
+			if err := tmpl.Execute(os.Stdout, info); err != nil {
+				logrus.Fatal(err)
+			}
+			fmt.Println()
+		}
+	}
 }
 
 // ProjectPort prints the public port for a port binding.
@@ -95,16 +147,36 @@ func ProjectPort(p *project.Project, c *cli.Context) {
 // ProjectDown brings all services down.
 func ProjectDown(p *project.Project, c *cli.Context) {
 	err := p.Down(c.Args()...)
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// ProjectTeardown stops and removes the containers of the project.
+func ProjectTeardown(p *project.Project, c *cli.Context) {
+	imageType, err := project.ParseImageType(c.String("rmi"))
 	if err != nil {
 		logrus.Fatal(err)
 	}
+
+	if !c.Bool("remove-orphans") {
+		removeOrphans(p, false)
+	}
+
+	err = p.Teardown(project.DownOptions{
+		RemoveImages:  imageType,
+		RemoveOrphans: c.Bool("remove-orphans"),
+	})
+	if err != nil {
+		fatal(err)
+	}
 }
 
 // ProjectBuild builds or rebuilds services.
 func ProjectBuild(p *project.Project, c *cli.Context) {
 	err := p.Build(c.Args()...)
 	if err != nil {
-		logrus.Fatal(err)
+		fatal(err)
 	}
 }
 
@@ -112,27 +184,214 @@ func ProjectBuild(p *project.Project, c *cli.Context) {
 func ProjectCreate(p *project.Project, c *cli.Context) {
 	err := p.Create(c.Args()...)
 	if err != nil {
-		logrus.Fatal(err)
+		fatal(err)
 	}
 }
 
-// ProjectUp brings all services up.
+// ProjectUp brings all services up. Unless detached, it then waits for the
+// containers to exit: the first SIGINT or SIGTERM stops the services, the
+// second one kills them.
 func ProjectUp(p *project.Project, c *cli.Context) {
+	exitCodeFrom := c.String("exit-code-from")
+	abort := c.Bool("abort-on-container-exit") || exitCodeFrom != ""
+
+	if c.Bool("d") && abort {
+		logrus.Fatal("-d can't be used with --abort-on-container-exit or --exit-code-from")
+	}
+	if _, ok := p.Configs[exitCodeFrom]; exitCodeFrom != "" && !ok {
+		logrus.Fatalf("No such service: %s", exitCodeFrom)
+	}
+
+	removeOrphans(p, c.Bool("remove-orphans"))
+
 	err := p.Up(c.Args()...)
+	if err != nil {
+		fatal(err)
+	}
+
+	if c.Bool("d") {
+		return
+	}
+
+	// Up also started the dependencies of the named services
+	services, err := p.ExpandSelection(c.Args(), project.SELECT_WITH_DEPENDENCIES)
+	if err != nil {
+		fatal(err)
+	}
+
+	os.Exit(waitForExit(p, services, abort, exitCodeFrom))
+}
+
+// waitForExit waits for the containers of the specified services to exit,
+// handles the signals sent meanwhile and returns the exit code of the CLI.
+func waitForExit(p *project.Project, services []string, abort bool, exitCodeFrom string) int {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	exits, err := p.WaitForExit(services...)
 	if err != nil {
 		logrus.Fatal(err)
 	}
 
-	if !c.Bool("d") {
-		wait()
+	exitCode := 0
+	stopping := false
+
+	// Down and Kill run in the background so that signals are still handled,
+	// and the CLI only exits once they completed.
+	running := 0
+	finished := make(chan struct{})
+	background := func(action func(...string) error) {
+		running++
+		go func() {
+			if err := action(services...); err != nil {
+				logrus.Error(err)
+			}
+			finished <- struct{}{}
+		}()
+	}
+
+	for {
+		select {
+		case exit, ok := <-exits:
+			if !ok {
+				exits = nil
+				break
+			}
+
+			if exit.Err != nil {
+				logrus.Errorf("Failed to wait for %s: %v", exit.Container, exit.Err)
+				if exit.Service == exitCodeFrom {
+					exitCode = 1
+				}
+			} else {
+				logrus.Infof("%s exited with code %d", exit.Container, exit.ExitCode)
+				if exit.Service == exitCodeFrom {
+					exitCode = exit.ExitCode
+				}
+			}
+
+			if abort && !stopping {
+				logrus.Info("Aborting on container exit...")
+				stopping = true
+				background(p.Down)
+			}
+		case <-signals:
+			if !stopping {
+				logrus.Info("Gracefully stopping... (press Ctrl+C again to force)")
+				stopping = true
+				background(p.Down)
+			} else {
+				logrus.Info("Killing...")
+				background(p.Kill)
+			}
+		case <-finished:
+			running--
+		}
+
+		if exits == nil && running == 0 {
+			return exitCode
+		}
+	}
+}
+
+// ProjectRun runs a one-off container of a service and exits with its exit
+// code.
+func ProjectRun(p *project.Project, c *cli.Context) {
+	if len(c.Args()) < 1 {
+		logrus.Fatal("Please pass arguments in the form: SERVICE [COMMAND...]")
+	}
+
+	entrypoint, err := shlex.Split(c.String("entrypoint"))
+	if err != nil {
+		logrus.Fatalf("Invalid entrypoint: %v", err)
+	}
+
+	options := project.RunOptions{
+		Command:     c.Args()[1:],
+		Entrypoint:  entrypoint,
+		Environment: c.StringSlice("e"),
+		User:        c.String("user"),
+		Remove:      c.Bool("rm"),
+		NoDeps:      c.Bool("no-deps"),
+		Stdin:       os.Stdin,
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+	}
+
+	inFd, isTerminal := term.GetFdInfo(os.Stdin)
+	options.Tty = isTerminal && !c.Bool("T")
+
+	var state *term.State
+	if options.Tty {
+		var err error
+		if state, err = term.SetRawTerminal(inFd); err != nil {
+			logrus.Fatal(err)
+		}
 	}
+
+	exitCode, err := p.Run(c.Args()[0], options)
+
+	if state != nil {
+		term.RestoreTerminal(inFd, state)
+	}
+	if err != nil {
+		fatal(err)
+	}
+
+	os.Exit(exitCode)
+}
+
+// ProjectExec runs a command in a running container of a service and exits
+// with its exit code.
+func ProjectExec(p *project.Project, c *cli.Context) {
+	if len(c.Args()) < 2 {
+		logrus.Fatal("Please pass arguments in the form: SERVICE COMMAND...")
+	}
+
+	index := c.Int("index")
+
+	service, err := p.CreateService(c.Args()[0])
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	options := project.ExecOptions{
+		Command: c.Args()[1:],
+		User:    c.String("user"),
+		Stdin:   os.Stdin,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+	}
+
+	inFd, stdinIsTerminal := term.GetFdInfo(os.Stdin)
+	_, stdoutIsTerminal := term.GetFdInfo(os.Stdout)
+	options.Tty = stdoutIsTerminal && !c.Bool("T")
+
+	var state *term.State
+	if options.Tty && stdinIsTerminal {
+		if state, err = term.SetRawTerminal(inFd); err != nil {
+			logrus.Fatal(err)
+		}
+	}
+
+	exitCode, err := service.Exec(index, options)
+
+	if state != nil {
+		term.RestoreTerminal(inFd, state)
+	}
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	os.Exit(exitCode)
 }
 
 // ProjectStart starts services.
 func ProjectStart(p *project.Project, c *cli.Context) {
 	err := p.Start(c.Args()...)
 	if err != nil {
-		logrus.Fatal(err)
+		fatal(err)
 	}
 }
 
@@ -140,7 +399,7 @@ func ProjectStart(p *project.Project, c *cli.Context) {
 func ProjectRestart(p *project.Project, c *cli.Context) {
 	err := p.Restart(c.Args()...)
 	if err != nil {
-		logrus.Fatal(err)
+		fatal(err)
 	}
 }
 
@@ -148,16 +407,36 @@ func ProjectRestart(p *project.Project, c *cli.Context) {
 func ProjectLog(p *project.Project, c *cli.Context) {
 	err := p.Log(c.Args()...)
 	if err != nil {
-		logrus.Fatal(err)
+		fatal(err)
+	}
+	if !c.Bool("no-follow") {
+		wait()
 	}
-	wait()
 }
 
 // ProjectPull pulls images for services.
 func ProjectPull(p *project.Project, c *cli.Context) {
+	_, terminal := term.GetFdInfo(os.Stdout)
+	progress := newImageProgress(os.Stdout, terminal, pullEvents)
+	p.AddListener(progress.events)
+
 	err := p.Pull(c.Args()...)
+	progress.Close()
 	if err != nil {
-		logrus.Fatal(err)
+		fatal(err)
+	}
+}
+
+// ProjectPush pushes the images built for services.
+func ProjectPush(p *project.Project, c *cli.Context) {
+	_, terminal := term.GetFdInfo(os.Stdout)
+	progress := newImageProgress(os.Stdout, terminal, pushEvents)
+	p.AddListener(progress.events)
+
+	err := p.Push(c.Args()...)
+	progress.Close()
+	if err != nil {
+		fatal(err)
 	}
 }
 
@@ -168,16 +447,67 @@ func ProjectDelete(p *project.Project, c *cli.Context) {
 	}
 	err := p.Delete(c.Args()...)
 	if err != nil {
-		logrus.Fatal(err)
+		fatal(err)
 	}
 }
 
 // ProjectKill forces stop service containers.
 func ProjectKill(p *project.Project, c *cli.Context) {
 	err := p.Kill(c.Args()...)
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// ProjectLock writes the digests of the images of services to the lock file.
+func ProjectLock(p *project.Project, c *cli.Context) {
+	lock, err := p.Lock(c.Args()...)
+	if err != nil {
+		fatal(err)
+	}
+
+	names := []string{}
+	for name := range lock.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Printf("%s: %s\n", name, lock.Services[name].Pinned())
+	}
+}
+
+// ProjectPause pauses service containers.
+func ProjectPause(p *project.Project, c *cli.Context) {
+	err := p.Pause(c.Args()...)
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// ProjectUnpause unpauses service containers.
+func ProjectUnpause(p *project.Project, c *cli.Context) {
+	err := p.Unpause(c.Args()...)
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// ProjectGraph prints the dependency graph of the services.
+func ProjectGraph(p *project.Project, c *cli.Context) {
+	graph, err := p.DependencyGraph()
 	if err != nil {
 		logrus.Fatal(err)
 	}
+
+	switch c.String("format") {
+	case "dot":
+		fmt.Print(graph.DOT(p.Name))
+	case "mermaid":
+		fmt.Print(graph.Mermaid())
+	default:
+		logrus.Fatalf("Invalid graph format %s, expected dot or mermaid", c.String("format"))
+	}
 }
 
 // ProjectScale scales services.
@@ -224,6 +554,58 @@ func ProjectScale(p *project.Project, c *cli.Context) {
 	}
 }
 
+// removeOrphans removes the orphan containers of the project if remove is
+// set, and otherwise warns about them.
+func removeOrphans(p *project.Project, remove bool) {
+	if remove {
+		if err := p.RemoveOrphans(); err != nil {
+			logrus.Fatal(err)
+		}
+		return
+	}
+
+	warnOrphans(p, "you can run this command with the --remove-orphans flag to clean it up")
+}
+
+// warnOrphans warns about the orphan containers of the project, with the
+// specified hint on how to remove them.
+func warnOrphans(p *project.Project, hint string) {
+	orphans, err := p.Orphans()
+	if err != nil {
+		logrus.Warnf("Failed to look for orphan containers: %v", err)
+	} else if len(orphans) > 0 {
+		logrus.Warnf("Found orphan containers (%s) for this project. If you removed or renamed this service in your compose file, %s.", strings.Join(orphans, ", "), hint)
+	}
+}
+
+// fatal logs the specified error and exits. When several services failed,
+// the failures are summarized per service.
+func fatal(err error) {
+	multi, ok := err.(*utils.MultiError)
+	if !ok || len(multi.Services()) == 0 {
+		logrus.Fatal(err)
+	}
+
+	byService := multi.ByService()
+	for _, err := range byService[""] {
+		logrus.Error(err)
+	}
+
+	services := multi.Services()
+	for _, name := range services {
+		logrus.Errorf("%s failed:", name)
+		for _, err := range byService[name] {
+			if err.Container != "" {
+				logrus.Errorf("  %s: %v", err.Container, err.Err)
+			} else {
+				logrus.Errorf("  %v", err.Err)
+			}
+		}
+	}
+
+	logrus.Fatalf("Failed services: %s", strings.Join(services, ", "))
+}
+
 func wait() {
 	<-make(chan interface{})
 }
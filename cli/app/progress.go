@@ -0,0 +1,159 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/pkg/units"
+	"github.com/docker/libcompose/project"
+)
+
+// progressEvents are the events of an operation that imageProgress renders.
+type progressEvents struct {
+	start, progress, done, failed project.Event
+	verb, pastVerb, failure       string
+}
+
+var (
+	pullEvents = progressEvents{
+		start:    project.SERVICE_PULL_START,
+		progress: project.SERVICE_PULL_PROGRESS,
+		done:     project.SERVICE_PULL,
+		failed:   project.SERVICE_PULL_FAILED,
+		verb:     "Pulling",
+		pastVerb: "Pulled",
+		failure:  "Failed to pull",
+	}
+	pushEvents = progressEvents{
+		start:    project.SERVICE_PUSH_START,
+		progress: project.SERVICE_PUSH_PROGRESS,
+		done:     project.SERVICE_PUSH,
+		failed:   project.SERVICE_PUSH_FAILED,
+		verb:     "Pushing",
+		pastVerb: "Pushed",
+		failure:  "Failed to push",
+	}
+)
+
+// imageProgress renders the pull or push events of a project, with one line
+// per image followed by one line per layer. On a terminal the lines are
+// redrawn in place, otherwise a line is printed each time a status changes.
+type imageProgress struct {
+	out      io.Writer
+	terminal bool
+	kind     progressEvents
+	events   chan project.ProjectEvent
+	done     chan struct{}
+
+	keys     []string
+	lines    map[string]string
+	statuses map[string]string
+	services map[string][]string
+	drawn    int
+}
+
+func newImageProgress(out io.Writer, terminal bool, kind progressEvents) *imageProgress {
+	p := &imageProgress{
+		out:      out,
+		terminal: terminal,
+		kind:     kind,
+		events:   make(chan project.ProjectEvent, 1024),
+		done:     make(chan struct{}),
+		lines:    map[string]string{},
+		statuses: map[string]string{},
+		services: map[string][]string{},
+	}
+	go p.start()
+	return p
+}
+
+// Close waits for the pending events to be rendered. The project must not
+// send events anymore.
+func (p *imageProgress) Close() {
+	close(p.events)
+	<-p.done
+}
+
+func (p *imageProgress) start() {
+	defer close(p.done)
+
+	for event := range p.events {
+		image := event.Data[project.PULL_IMAGE]
+		if image == "" {
+			continue
+		}
+
+		services := strings.Join(p.services[image], ", ")
+
+		switch event.Event {
+		case p.kind.start:
+			p.services[image] = append(p.services[image], event.ServiceName)
+			services = strings.Join(p.services[image], ", ")
+			p.set(image, image, "start", fmt.Sprintf("%s %s (%s)...", p.kind.verb, services, image))
+		case p.kind.done:
+			p.set(image, image, "done", fmt.Sprintf("%s %s (%s)", p.kind.pastVerb, services, image))
+		case p.kind.failed:
+			p.set(image, image, "failed", fmt.Sprintf("%s %s (%s): %s", p.kind.failure, services, image, event.Data[project.PULL_ERROR]))
+		case p.kind.progress:
+			layer := event.Data[project.PULL_LAYER]
+			if layer == "" {
+				continue
+			}
+			status := event.Data[project.PULL_STATUS]
+			p.set(image, image+" "+layer, status, fmt.Sprintf("  %s: %s%s", layer, status, progressBytes(event.Data)))
+		default:
+			continue
+		}
+
+		if p.terminal {
+			p.redraw()
+		}
+	}
+}
+
+// set updates the line of the key, adding it after the last line of the
+// image if it is new.
+func (p *imageProgress) set(image, key, status, line string) {
+	if _, ok := p.lines[key]; !ok {
+		index := len(p.keys)
+		for i := len(p.keys) - 1; i >= 0; i-- {
+			if p.keys[i] == image || strings.HasPrefix(p.keys[i], image+" ") {
+				index = i + 1
+				break
+			}
+		}
+		p.keys = append(p.keys[:index], append([]string{key}, p.keys[index:]...)...)
+	}
+
+	changed := p.statuses[key] != status
+	p.lines[key] = line
+	p.statuses[key] = status
+
+	if !p.terminal && changed {
+		fmt.Fprintln(p.out, strings.TrimSpace(line))
+	}
+}
+
+func (p *imageProgress) redraw() {
+	if p.drawn > 0 {
+		fmt.Fprintf(p.out, "\033[%dA", p.drawn)
+	}
+	for _, key := range p.keys {
+		fmt.Fprintf(p.out, "\033[2K\r%s\n", p.lines[key])
+	}
+	p.drawn = len(p.keys)
+}
+
+func progressBytes(data map[string]string) string {
+	current, err := strconv.ParseFloat(data[project.PULL_CURRENT], 64)
+	if err != nil {
+		return ""
+	}
+	total, err := strconv.ParseFloat(data[project.PULL_TOTAL], 64)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf(" %s/%s", units.HumanSize(current), units.HumanSize(total))
+}
@@ -1,8 +1,14 @@
 package command
 
 import (
+	"os"
+	"time"
+
+	"github.com/Sirupsen/logrus"
 	"github.com/codegangsta/cli"
 	"github.com/docker/libcompose/cli/app"
+	cliLogger "github.com/docker/libcompose/cli/logger"
+	"github.com/docker/libcompose/logger"
 	"github.com/docker/libcompose/project"
 )
 
@@ -12,6 +18,7 @@ func CreateCommand(factory app.ProjectFactory) cli.Command {
 		Name:   "create",
 		Usage:  "Create all services but do not start",
 		Action: app.WithProject(factory, app.ProjectCreate),
+		Flags:  []cli.Flag{noDepsFlag(), withDependentsFlag()},
 	}
 }
 
@@ -30,6 +37,22 @@ func PsCommand(factory app.ProjectFactory) cli.Command {
 		Name:   "ps",
 		Usage:  "List containers",
 		Action: app.WithProject(factory, app.ProjectPs),
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: table, json or a Go template like '{{.Name}} {{.Status}}'",
+				Value: "table",
+			},
+			cli.BoolFlag{
+				Name:  "q",
+				Usage: "Only display IDs",
+			},
+			cli.StringSliceFlag{
+				Name:  "filter",
+				Usage: "Filter containers by service, status, name or health, like service=web,status=running",
+				Value: &cli.StringSlice{},
+			},
+		},
 	}
 }
 
@@ -65,6 +88,84 @@ func UpCommand(factory app.ProjectFactory) cli.Command {
 				Name:  "d",
 				Usage: "Do not block and log",
 			},
+			cli.BoolFlag{
+				Name:  "abort-on-container-exit",
+				Usage: "Stop all containers if any container exits. Incompatible with -d.",
+			},
+			cli.StringFlag{
+				Name:  "exit-code-from",
+				Usage: "Return the exit code of the selected service container. Implies --abort-on-container-exit.",
+			},
+			cli.IntFlag{
+				Name:  "timeout,t",
+				Usage: "Specify a shutdown timeout in seconds.",
+				Value: 10,
+			},
+			cli.StringFlag{
+				Name:  "pull",
+				Usage: "Pull the images before starting the containers: always, missing or never (default: the pull_policy of each service)",
+			},
+			enforceLockFlag(),
+			removeOrphansFlag(),
+			noDepsFlag(),
+			withDependentsFlag(),
+		},
+	}
+}
+
+// RunCommand defines the libcompose run subcommand.
+func RunCommand(factory app.ProjectFactory) cli.Command {
+	return cli.Command{
+		Name:   "run",
+		Usage:  "Run a one-off command on a service: run SERVICE [COMMAND...]",
+		Action: app.WithProject(factory, app.ProjectRun),
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "rm",
+				Usage: "Remove the container after it exits",
+			},
+			cli.StringFlag{
+				Name:  "entrypoint",
+				Usage: "Override the entrypoint of the service",
+			},
+			cli.StringSliceFlag{
+				Name:  "e",
+				Usage: "Set an environment variable (KEY=VALUE), can be used multiple times",
+				Value: &cli.StringSlice{},
+			},
+			cli.StringFlag{
+				Name:  "user,u",
+				Usage: "Run as the specified username or uid",
+			},
+			cli.BoolFlag{
+				Name:  "T",
+				Usage: "Disable pseudo-tty allocation",
+			},
+			noDepsFlag(),
+		},
+	}
+}
+
+// ExecCommand defines the libcompose exec subcommand.
+func ExecCommand(factory app.ProjectFactory) cli.Command {
+	return cli.Command{
+		Name:   "exec",
+		Usage:  "Execute a command in a running container: exec SERVICE COMMAND...",
+		Action: app.WithProject(factory, app.ProjectExec),
+		Flags: []cli.Flag{
+			cli.IntFlag{
+				Name:  "index",
+				Usage: "index of the container if there are multiple instances of a service",
+				Value: 1,
+			},
+			cli.StringFlag{
+				Name:  "user,u",
+				Usage: "Run the command as this user",
+			},
+			cli.BoolFlag{
+				Name:  "T",
+				Usage: "Disable pseudo-tty allocation",
+			},
 		},
 	}
 }
@@ -80,6 +181,7 @@ func StartCommand(factory app.ProjectFactory) cli.Command {
 				Name:  "d",
 				Usage: "Do not block and log",
 			},
+			withDependentsFlag(),
 		},
 	}
 }
@@ -90,6 +192,13 @@ func PullCommand(factory app.ProjectFactory) cli.Command {
 		Name:   "pull",
 		Usage:  "Pulls images for services",
 		Action: app.WithProject(factory, app.ProjectPull),
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "ignore-pull-failures",
+				Usage: "Pull what it can and ignores images with pull failures.",
+			},
+			enforceLockFlag(),
+		},
 	}
 }
 
@@ -102,9 +211,21 @@ func LogsCommand(factory app.ProjectFactory) cli.Command {
 		Flags: []cli.Flag{
 			cli.IntFlag{
 				Name:  "lines",
-				Usage: "number of lines to tail",
+				Usage: "number of lines to tail, 0 for all",
 				Value: 100,
 			},
+			cli.BoolFlag{
+				Name:  "no-follow",
+				Usage: "print the logs and exit instead of following them",
+			},
+			cli.BoolFlag{
+				Name:  "timestamps,t",
+				Usage: "show timestamps",
+			},
+			cli.StringFlag{
+				Name:  "since",
+				Usage: "only show logs since a timestamp (2015-10-28T15:04:05Z) or a relative duration (10m)",
+			},
 		},
 	}
 }
@@ -121,6 +242,7 @@ func RestartCommand(factory app.ProjectFactory) cli.Command {
 				Usage: "Specify a shutdown timeout in seconds.",
 				Value: 10,
 			},
+			withDependentsFlag(),
 		},
 	}
 }
@@ -128,16 +250,38 @@ func RestartCommand(factory app.ProjectFactory) cli.Command {
 // StopCommand defines the libcompose stop subcommand.
 func StopCommand(factory app.ProjectFactory) cli.Command {
 	return cli.Command{
-		Name:      "stop",
-		ShortName: "down",
-		Usage:     "Stop services",
-		Action:    app.WithProject(factory, app.ProjectDown),
+		Name:   "stop",
+		Usage:  "Stop services",
+		Action: app.WithProject(factory, app.ProjectDown),
 		Flags: []cli.Flag{
 			cli.IntFlag{
 				Name:  "timeout,t",
 				Usage: "Specify a shutdown timeout in seconds.",
 				Value: 10,
 			},
+			withDependentsFlag(),
+		},
+	}
+}
+
+// DownCommand defines the libcompose down subcommand.
+func DownCommand(factory app.ProjectFactory) cli.Command {
+	return cli.Command{
+		Name:   "down",
+		Usage:  "Stop and remove containers, and optionally volumes, images and orphan containers",
+		Action: app.WithProject(factory, app.ProjectTeardown),
+		Flags: []cli.Flag{
+			cli.IntFlag{
+				Name:  "timeout,t",
+				Usage: "Specify a shutdown timeout in seconds.",
+				Value: 10,
+			},
+			volumesFlag(),
+			cli.StringFlag{
+				Name:  "rmi",
+				Usage: "Remove images: 'local' for the images built by the project, 'all' for every image used by the services",
+			},
+			removeOrphansFlag(),
 		},
 	}
 }
@@ -169,6 +313,8 @@ func RmCommand(factory app.ProjectFactory) cli.Command {
 				Name:  "force,f",
 				Usage: "Allow deletion of all services",
 			},
+			volumesFlag(),
+			withDependentsFlag(),
 		},
 	}
 }
@@ -185,10 +331,100 @@ func KillCommand(factory app.ProjectFactory) cli.Command {
 				Usage: "SIGNAL to send to the container",
 				Value: "SIGKILL",
 			},
+			withDependentsFlag(),
+		},
+	}
+}
+
+// PushCommand defines the libcompose push subcommand.
+func PushCommand(factory app.ProjectFactory) cli.Command {
+	return cli.Command{
+		Name:   "push",
+		Usage:  "Push the images built for services",
+		Action: app.WithProject(factory, app.ProjectPush),
+	}
+}
+
+// LockCommand defines the libcompose lock subcommand.
+func LockCommand(factory app.ProjectFactory) cli.Command {
+	return cli.Command{
+		Name:   "lock",
+		Usage:  "Pin the images of services to their digest in a lock file",
+		Action: app.WithProject(factory, app.ProjectLock),
+	}
+}
+
+// PauseCommand defines the libcompose pause subcommand.
+func PauseCommand(factory app.ProjectFactory) cli.Command {
+	return cli.Command{
+		Name:   "pause",
+		Usage:  "Pause services",
+		Action: app.WithProject(factory, app.ProjectPause),
+		Flags:  []cli.Flag{withDependentsFlag()},
+	}
+}
+
+// UnpauseCommand defines the libcompose unpause subcommand.
+func UnpauseCommand(factory app.ProjectFactory) cli.Command {
+	return cli.Command{
+		Name:   "unpause",
+		Usage:  "Unpause services",
+		Action: app.WithProject(factory, app.ProjectUnpause),
+		Flags:  []cli.Flag{withDependentsFlag()},
+	}
+}
+
+// GraphCommand defines the libcompose graph subcommand.
+func GraphCommand(factory app.ProjectFactory) cli.Command {
+	return cli.Command{
+		Name:   "graph",
+		Usage:  "Print the dependency graph of the services",
+		Action: app.WithProject(factory, app.ProjectGraph),
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "format",
+				Usage: "Output format: dot or mermaid",
+				Value: "dot",
+			},
 		},
 	}
 }
 
+func noDepsFlag() cli.Flag {
+	return cli.BoolFlag{
+		Name:  "no-deps",
+		Usage: "Don't include the services the named services depend on",
+	}
+}
+
+func withDependentsFlag() cli.Flag {
+	return cli.BoolFlag{
+		Name:  "with-dependents",
+		Usage: "Include the services that depend on the named services",
+	}
+}
+
+func removeOrphansFlag() cli.Flag {
+	return cli.BoolFlag{
+		Name:  "remove-orphans",
+		Usage: "Remove containers of services not defined in the compose file",
+	}
+}
+
+func enforceLockFlag() cli.Flag {
+	return cli.BoolFlag{
+		Name:  "enforce-lock",
+		Usage: "Use the images pinned by the lock file, and fail if an image isn't locked or its tag moved",
+	}
+}
+
+func volumesFlag() cli.Flag {
+	return cli.BoolFlag{
+		Name:  "v",
+		Usage: "Remove the anonymous volumes attached to the containers",
+	}
+}
+
 // CommonFlags defines the flags that are in common for all subcommands.
 func CommonFlags() []cli.Flag {
 	return []cli.Flag{
@@ -205,6 +441,19 @@ func CommonFlags() []cli.Flag {
 			Name:  "project-name,p",
 			Usage: "Specify an alternate project name (default: directory name)",
 		},
+		cli.IntFlag{
+			Name:  "parallel",
+			Usage: "Maximum number of services and containers to operate on at the same time (default: unlimited)",
+		},
+		cli.StringFlag{
+			Name:  "log-format",
+			Usage: "Format of the container logs printed on stdout: color or json",
+			Value: "color",
+		},
+		cli.StringFlag{
+			Name:  "log-dir",
+			Usage: "Also append the container logs to a rotated file per container in this directory",
+		},
 	}
 }
 
@@ -212,14 +461,70 @@ func CommonFlags() []cli.Flag {
 func Populate(context *project.Context, c *cli.Context) {
 	context.ComposeFile = c.GlobalString("file")
 	context.ProjectName = c.GlobalString("project-name")
+	context.Parallelism = c.GlobalInt("parallel")
+	context.LoggerFactory = loggerFactory(c.GlobalString("log-format"), c.GlobalString("log-dir"))
 
 	if c.Command.Name == "logs" {
+		since, err := project.ParseSince(c.String("since"), time.Now())
+		if err != nil {
+			logrus.Fatal(err)
+		}
+
 		context.Log = true
+		context.LogOptions = project.LogOptions{
+			Tail:       c.Int("lines"),
+			Follow:     !c.Bool("no-follow"),
+			Timestamps: c.Bool("timestamps"),
+			Since:      since,
+		}
 	} else if c.Command.Name == "up" {
 		context.Log = !c.Bool("d")
+		context.Timeout = c.Int("timeout")
+
+		policy, err := project.ParsePullPolicy(c.String("pull"))
+		if err != nil || policy == project.PULL_POLICY_BUILD {
+			logrus.Fatalf("Invalid value %q for --pull, expected always, missing or never", c.String("pull"))
+		}
+		context.PullPolicy = policy
+		context.EnforceLock = c.Bool("enforce-lock")
 	} else if c.Command.Name == "stop" || c.Command.Name == "restart" || c.Command.Name == "scale" {
 		context.Timeout = c.Int("timeout")
+	} else if c.Command.Name == "down" {
+		context.Timeout = c.Int("timeout")
+		context.RemoveVolumes = c.Bool("v")
+	} else if c.Command.Name == "rm" {
+		context.RemoveVolumes = c.Bool("v")
+	} else if c.Command.Name == "pull" {
+		context.IgnorePullFailures = c.Bool("ignore-pull-failures")
+		context.EnforceLock = c.Bool("enforce-lock")
 	} else if c.Command.Name == "kill" {
 		context.Signal = c.String("signal")
 	}
+
+	if c.Bool("no-deps") && c.Bool("with-dependents") {
+		logrus.Fatal("--no-deps and --with-dependents can't be used together")
+	} else if c.Bool("no-deps") {
+		context.Selection = project.SELECT_NAMED
+	} else if c.Bool("with-dependents") {
+		context.Selection = project.SELECT_WITH_DEPENDENTS
+	}
+}
+
+func loggerFactory(format, dir string) logger.Factory {
+	var factory logger.Factory
+
+	switch format {
+	case "color":
+		factory = cliLogger.NewColorLoggerFactory()
+	case "json":
+		factory = logger.NewJsonLoggerFactory(os.Stdout)
+	default:
+		logrus.Fatalf("Invalid log format %s, expected color or json", format)
+	}
+
+	if dir != "" {
+		factory = logger.NewMultiLoggerFactory(factory, logger.NewFileLoggerFactory(dir, logger.DefaultMaxSize, logger.DefaultMaxFiles))
+	}
+
+	return factory
 }
@@ -3,7 +3,6 @@ package app
 import (
 	"github.com/codegangsta/cli"
 	"github.com/docker/libcompose/cli/command"
-	"github.com/docker/libcompose/cli/logger"
 	"github.com/docker/libcompose/docker"
 	"github.com/docker/libcompose/project"
 )
@@ -15,7 +14,6 @@ type ProjectFactory struct {
 // Create implements ProjectFactory.Create using docker client.
 func (p *ProjectFactory) Create(c *cli.Context) (*project.Project, error) {
 	context := &docker.Context{}
-	context.LoggerFactory = logger.NewColorLoggerFactory()
 	Populate(context, c)
 	command.Populate(&context.Context, c)
 
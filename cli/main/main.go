@@ -25,16 +25,24 @@ func main() {
 		command.BuildCommand(factory),
 		command.CreateCommand(factory),
 		command.UpCommand(factory),
+		command.RunCommand(factory),
+		command.ExecCommand(factory),
 		command.StartCommand(factory),
 		command.LogsCommand(factory),
 		command.RestartCommand(factory),
 		command.StopCommand(factory),
+		command.DownCommand(factory),
 		command.ScaleCommand(factory),
 		command.RmCommand(factory),
 		command.PullCommand(factory),
+		command.PushCommand(factory),
+		command.LockCommand(factory),
 		command.KillCommand(factory),
+		command.PauseCommand(factory),
+		command.UnpauseCommand(factory),
 		command.PortCommand(factory),
 		command.PsCommand(factory),
+		command.GraphCommand(factory),
 	}
 
 	app.Run(os.Args)
@@ -0,0 +1,264 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/docker/libcompose/project"
+	"github.com/samalba/dockerclient"
+)
+
+// The following helpers call the Docker remote API directly, for the
+// endpoints and fields that dockerclient doesn't support. They only work
+// with the default dockerclient implementation and return
+// project.ErrUnsupported otherwise.
+
+func apiStream(client dockerclient.Client, method, path string, body io.Reader, headers map[string]string) (io.ReadCloser, error) {
+	resp, err := apiDo(client, method, path, body, headers)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func apiDo(client dockerclient.Client, method, path string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	dockerClient, ok := client.(*dockerclient.DockerClient)
+	if !ok {
+		return nil, project.ErrUnsupported
+	}
+
+	if (method == "POST" || method == "PUT") && body == nil {
+		body = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, dockerClient.URL.String()+apiPath(path), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for header, value := range headers {
+		req.Header.Set(header, value)
+	}
+
+	resp, err := dockerClient.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, dockerclient.ErrNotFound
+	}
+
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	return resp, nil
+}
+
+// apiHijack sends the request and returns the connection to the daemon once
+// it answered, to stream the standard input and output of a container. The
+// connection is dialed directly, like the Docker client does, since
+// net/http doesn't hand over the connection of a response.
+func apiHijack(client dockerclient.Client, method, path string, in interface{}) (io.ReadWriteCloser, error) {
+	dockerClient, ok := client.(*dockerclient.DockerClient)
+	if !ok {
+		return nil, project.ErrUnsupported
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, dockerClient.URL.String()+apiPath(path), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "tcp")
+
+	conn, err := dialDaemon(dockerClient)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		conn.Close()
+		return nil, dockerclient.ErrNotFound
+	}
+
+	if resp.StatusCode >= 400 {
+		defer conn.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	// Whether the daemon upgraded the connection (101) or not (200, before
+	// API 1.17), the stream follows the headers.
+	return &hijackedConn{Conn: conn, reader: reader}, nil
+}
+
+// dialDaemon opens a connection to the daemon of the client, through the
+// dialer of its transport, which knows about unix sockets, and with its TLS
+// configuration.
+func dialDaemon(client *dockerclient.DockerClient) (net.Conn, error) {
+	dial := net.Dial
+	if transport, ok := client.HTTPClient.Transport.(*http.Transport); ok && transport.Dial != nil {
+		dial = transport.Dial
+	}
+
+	conn, err := dial("tcp", client.URL.Host)
+	if err != nil || client.TLSConfig == nil {
+		return conn, err
+	}
+
+	config := &tls.Config{
+		Certificates:       client.TLSConfig.Certificates,
+		RootCAs:            client.TLSConfig.RootCAs,
+		InsecureSkipVerify: client.TLSConfig.InsecureSkipVerify,
+		ServerName:         client.TLSConfig.ServerName,
+		CipherSuites:       client.TLSConfig.CipherSuites,
+		MinVersion:         client.TLSConfig.MinVersion,
+		MaxVersion:         client.TLSConfig.MaxVersion,
+	}
+	if config.ServerName == "" {
+		if host, _, err := net.SplitHostPort(client.URL.Host); err == nil {
+			config.ServerName = host
+		} else {
+			config.ServerName = client.URL.Host
+		}
+	}
+
+	tlsConn := tls.Client(conn, config)
+	if err := tlsConn.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+// hijackedConn is a connection to the daemon that carries the standard
+// streams of a container, past the headers of the response.
+type hijackedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (h *hijackedConn) Read(p []byte) (int, error) {
+	return h.reader.Read(p)
+}
+
+// CloseWrite closes the input of the container, if the connection supports
+// it, so that the command sees the end of its input.
+func (h *hijackedConn) CloseWrite() error {
+	if conn, ok := h.Conn.(interface {
+		CloseWrite() error
+	}); ok {
+		return conn.CloseWrite()
+	}
+	return nil
+}
+
+// apiPath prefixes the path with the API version of dockerclient, unless it
+// already has a version, like /v1.25/containers/create.
+func apiPath(path string) string {
+	if len(path) > 2 && strings.HasPrefix(path, "/v") && path[2] >= '0' && path[2] <= '9' {
+		return path
+	}
+	return "/" + dockerclient.APIVersion + path
+}
+
+func apiJSON(client dockerclient.Client, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	stream, err := apiStream(client, method, path, body, nil)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	if out == nil {
+		_, err = io.Copy(ioutil.Discard, stream)
+		return err
+	}
+
+	return json.NewDecoder(stream).Decode(out)
+}
+
+// apiVersionAtLeast returns whether the daemon supports the specified API
+// version, like 1.24.
+func apiVersionAtLeast(client dockerclient.Client, version string) bool {
+	v, err := client.Version()
+	if err != nil {
+		return false
+	}
+
+	return compareVersions(v.ApiVersion, version) >= 0
+}
+
+func compareVersions(left, right string) int {
+	leftParts := strings.Split(left, ".")
+	rightParts := strings.Split(right, ".")
+
+	for i := 0; i < len(leftParts) || i < len(rightParts); i++ {
+		var l, r int
+		if i < len(leftParts) {
+			l, _ = strconv.Atoi(leftParts[i])
+		}
+		if i < len(rightParts) {
+			r, _ = strconv.Atoi(rightParts[i])
+		}
+		if l != r {
+			if l < r {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
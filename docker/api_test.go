@@ -0,0 +1,76 @@
+package docker
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/samalba/dockerclient"
+	"github.com/stretchr/testify/assert"
+)
+
+// newHijackServer returns a daemon that answers with the specified status
+// line, then echoes its input back once it ends.
+func newHijackServer(t *testing.T, status string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1.15/exec/abc/start", r.URL.Path)
+		assert.Equal(t, "tcp", r.Header.Get("Upgrade"))
+
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, `{"Detach":false}`, string(body))
+
+		conn, buffer, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		buffer.WriteString(status + "\r\n\r\n")
+		buffer.Flush()
+
+		input, err := ioutil.ReadAll(buffer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Write(input)
+	}))
+}
+
+func testApiHijack(t *testing.T, status string) {
+	server := newHijackServer(t, status)
+	defer server.Close()
+
+	client, err := dockerclient.NewDockerClient(server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := apiHijack(client, "POST", "/exec/abc/start", map[string]bool{"Detach": false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	_, err = stream.Write([]byte("hello\n"))
+	assert.Nil(t, err)
+	assert.Nil(t, stream.(*hijackedConn).CloseWrite())
+
+	output, err := ioutil.ReadAll(stream)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello\n", string(output))
+}
+
+func TestApiHijack(t *testing.T) {
+	testApiHijack(t, "HTTP/1.1 101 UPGRADED\r\nConnection: Upgrade\r\nUpgrade: tcp")
+}
+
+func TestApiHijackWithoutUpgrade(t *testing.T) {
+	testApiHijack(t, "HTTP/1.1 200 OK\r\nContent-Type: application/vnd.docker.raw-stream")
+}
+
+func TestApiPath(t *testing.T) {
+	assert.Equal(t, "/v1.15/containers/json", apiPath("/containers/json"))
+	assert.Equal(t, "/v1.25/containers/create", apiPath("/v1.25/containers/create"))
+	assert.Equal(t, "/v1.15/volumes", apiPath("/volumes"))
+}
@@ -0,0 +1,210 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/cliconfig"
+	"github.com/docker/docker/registry"
+	"github.com/samalba/dockerclient"
+)
+
+// credentialsNotFound is the message of the credential helpers that don't
+// have credentials for a registry.
+const credentialsNotFound = "credentials not found in native keychain"
+
+// AuthLookup resolves the credentials of registries for pulls, builds and
+// pushes, from the auths of the Docker config file, its credential store
+// (credsStore) and its per registry credential helpers (credHelpers).
+type AuthLookup struct {
+	configFile  *cliconfig.ConfigFile
+	credsStore  string
+	credHelpers map[string]string
+}
+
+// NewAuthLookup returns an AuthLookup that only uses the auths of the
+// config file, which may be nil.
+func NewAuthLookup(configFile *cliconfig.ConfigFile) *AuthLookup {
+	if configFile == nil {
+		configFile = cliconfig.NewConfigFile("")
+	}
+	return &AuthLookup{
+		configFile:  configFile,
+		credHelpers: map[string]string{},
+	}
+}
+
+// LoadAuthLookup reads the config.json file of the Docker config directory,
+// the default one if configDir is empty.
+func LoadAuthLookup(configDir string) (*AuthLookup, error) {
+	if configDir == "" {
+		configDir = cliconfig.ConfigDir()
+	}
+
+	filename := filepath.Join(configDir, cliconfig.ConfigFileName)
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		// Handles the legacy .dockercfg file
+		configFile, err := cliconfig.Load(configDir)
+		if err != nil {
+			return nil, err
+		}
+		return NewAuthLookup(configFile), nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// cliconfig doesn't support credential helpers, and fails on the auths
+	// that they leave empty
+	var config struct {
+		Auths       map[string]cliconfig.AuthConfig `json:"auths"`
+		CredsStore  string                          `json:"credsStore"`
+		CredHelpers map[string]string               `json:"credHelpers"`
+	}
+	if err := json.NewDecoder(file).Decode(&config); err != nil {
+		return nil, fmt.Errorf("Failed to parse %s: %v", filename, err)
+	}
+
+	lookup := NewAuthLookup(cliconfig.NewConfigFile(filename))
+	lookup.credsStore = config.CredsStore
+	if config.CredHelpers != nil {
+		lookup.credHelpers = config.CredHelpers
+	}
+
+	for address, auth := range config.Auths {
+		if auth.Auth != "" {
+			if auth.Username, auth.Password, err = cliconfig.DecodeAuth(auth.Auth); err != nil {
+				return nil, fmt.Errorf("Failed to decode the auth of %s in %s: %v", address, filename, err)
+			}
+		}
+		auth.Auth = ""
+		auth.ServerAddress = address
+		lookup.configFile.AuthConfigs[address] = auth
+	}
+
+	return lookup, nil
+}
+
+// Lookup returns the credentials of the registry of the repository, from
+// its credential helper if any, else from the config file.
+func (a *AuthLookup) Lookup(repository string) (*dockerclient.AuthConfig, error) {
+	repoInfo, err := registry.ParseRepositoryInfo(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	if helper := a.helper(repoInfo.Index.Name); helper != "" {
+		auth, err := credentialHelperGet(helper, repoInfo.Index.GetAuthConfigKey())
+		if err != nil {
+			return nil, err
+		}
+		if auth != nil {
+			return auth, nil
+		}
+	}
+
+	return toDockerclientAuth(registry.ResolveAuthConfig(a.configFile, repoInfo.Index)), nil
+}
+
+// All returns the credentials of every registry the config file knows
+// about, to send with builds. The registries whose helper fails are
+// skipped.
+func (a *AuthLookup) All() *dockerclient.ConfigFile {
+	result := &dockerclient.ConfigFile{
+		Configs: map[string]dockerclient.AuthConfig{},
+	}
+
+	for address, auth := range a.configFile.AuthConfigs {
+		if auth.Username != "" || auth.Password != "" {
+			result.Configs[address] = *toDockerclientAuth(auth)
+		}
+	}
+
+	addresses := map[string]string{}
+	if a.credsStore != "" {
+		var stored map[string]string
+		if err := credentialHelper(a.credsStore, "list", "", &stored); err != nil {
+			logrus.Warnf("Failed to list the credentials of %s: %v", a.credsStore, err)
+		}
+		for address := range stored {
+			addresses[address] = a.credsStore
+		}
+	}
+	for address, helper := range a.credHelpers {
+		addresses[address] = helper
+	}
+
+	for address, helper := range addresses {
+		auth, err := credentialHelperGet(helper, address)
+		if err != nil {
+			logrus.Warnf("Failed to get the credentials of %s from %s: %v", address, helper, err)
+			continue
+		}
+		if auth != nil {
+			result.Configs[address] = *auth
+		}
+	}
+
+	return result
+}
+
+func (a *AuthLookup) helper(hostname string) string {
+	if helper, ok := a.credHelpers[hostname]; ok {
+		return helper
+	}
+	return a.credsStore
+}
+
+func toDockerclientAuth(auth cliconfig.AuthConfig) *dockerclient.AuthConfig {
+	return &dockerclient.AuthConfig{
+		Username: auth.Username,
+		Password: auth.Password,
+		Email:    auth.Email,
+	}
+}
+
+// credentialHelperGet returns the credentials of the server from the
+// helper, or nil if it has none.
+func credentialHelperGet(helper, serverURL string) (*dockerclient.AuthConfig, error) {
+	var credentials struct {
+		Username string
+		Secret   string
+	}
+
+	err := credentialHelper(helper, "get", serverURL, &credentials)
+	if err != nil && strings.Contains(err.Error(), credentialsNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &dockerclient.AuthConfig{
+		Username: credentials.Username,
+		Password: credentials.Secret,
+	}, nil
+}
+
+// credentialHelper runs the docker-credential-<helper> program with the
+// action and the input on its standard input, and decodes its JSON output.
+func credentialHelper(helper, action, input string, out interface{}) error {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("docker-credential-"+helper, action)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimSpace(stdout.String() + " " + stderr.String())
+		return fmt.Errorf("docker-credential-%s %s: %v: %s", helper, action, err, message)
+	}
+
+	return json.Unmarshal(stdout.Bytes(), out)
+}
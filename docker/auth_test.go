@@ -0,0 +1,123 @@
+package docker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/samalba/dockerclient"
+	"github.com/stretchr/testify/assert"
+)
+
+const fakeStore = `#!/bin/sh
+read server
+case "$1" in
+get)
+	if [ "$server" = "https://index.docker.io/v1/" ]; then
+		echo '{"ServerURL":"https://index.docker.io/v1/","Username":"hub","Secret":"hubsecret"}'
+	else
+		echo "credentials not found in native keychain"
+		exit 1
+	fi
+	;;
+list)
+	echo '{"https://index.docker.io/v1/":"hub"}'
+	;;
+esac
+`
+
+const fakeGcrHelper = `#!/bin/sh
+read server
+echo '{"ServerURL":"'$server'","Username":"_token","Secret":"gcrsecret"}'
+`
+
+const fakeConfig = `{
+	"auths": {
+		"https://index.docker.io/v1/": {},
+		"private.registry:5000": {"auth": "dXNlcjpwYXNz"}
+	},
+	"credsStore": "fake",
+	"credHelpers": {"gcr.io": "fakegcr"}
+}`
+
+// withFakeHelpers writes a Docker config directory using fake credential
+// helpers, put on the PATH, and returns its AuthLookup.
+func withFakeHelpers(t *testing.T) (*AuthLookup, func()) {
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"docker-credential-fake":    fakeStore,
+		"docker-credential-fakegcr": fakeGcrHelper,
+		"config.json":               fakeConfig,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := os.Getenv("PATH")
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+path)
+
+	lookup, err := LoadAuthLookup(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return lookup, func() {
+		os.Setenv("PATH", path)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestAuthLookup(t *testing.T) {
+	lookup, cleanup := withFakeHelpers(t)
+	defer cleanup()
+
+	auth, err := lookup.Lookup("nginx")
+	assert.Nil(t, err)
+	assert.Equal(t, &dockerclient.AuthConfig{Username: "hub", Password: "hubsecret"}, auth)
+
+	auth, err = lookup.Lookup("gcr.io/project/app")
+	assert.Nil(t, err)
+	assert.Equal(t, &dockerclient.AuthConfig{Username: "_token", Password: "gcrsecret"}, auth)
+
+	auth, err = lookup.Lookup("private.registry:5000/app")
+	assert.Nil(t, err)
+	assert.Equal(t, &dockerclient.AuthConfig{Username: "user", Password: "pass"}, auth)
+
+	auth, err = lookup.Lookup("other.registry/app")
+	assert.Nil(t, err)
+	assert.Equal(t, &dockerclient.AuthConfig{}, auth)
+}
+
+func TestAuthLookupAll(t *testing.T) {
+	lookup, cleanup := withFakeHelpers(t)
+	defer cleanup()
+
+	assert.Equal(t, map[string]dockerclient.AuthConfig{
+		"https://index.docker.io/v1/": {Username: "hub", Password: "hubsecret"},
+		"gcr.io":                      {Username: "_token", Password: "gcrsecret"},
+		"private.registry:5000":       {Username: "user", Password: "pass"},
+	}, lookup.All().Configs)
+}
+
+func TestAuthLookupWithoutConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	lookup, err := LoadAuthLookup(dir)
+	assert.Nil(t, err)
+
+	auth, err := lookup.Lookup("nginx")
+	assert.Nil(t, err)
+	assert.Equal(t, &dockerclient.AuthConfig{}, auth)
+	assert.Empty(t, lookup.All().Configs)
+}
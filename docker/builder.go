@@ -38,7 +38,7 @@ func (d *DaemonBuilder) Build(p *project.Project, service project.Service) (stri
 		return service.Config().Image, nil
 	}
 
-	tag := fmt.Sprintf("%s_%s", p.Name, service.Name())
+	tag := builtImageName(p, service)
 	context, err := CreateTar(p, service.Name())
 	if err != nil {
 		return "", err
@@ -51,6 +51,7 @@ func (d *DaemonBuilder) Build(p *project.Project, service project.Service) (stri
 	logrus.Infof("Building %s...", tag)
 	output, err := client.BuildImage(&dockerclient.BuildImage{
 		Context:        context,
+		Config:         d.context.authLookup().All(),
 		RepoName:       tag,
 		Remove:         true,
 		DockerfileName: service.Config().Dockerfile,
@@ -75,6 +76,15 @@ func (d *DaemonBuilder) Build(p *project.Project, service project.Service) (stri
 	return tag, nil
 }
 
+// builtImageName returns the name of the image built for the service: its
+// image if specified, else project_service.
+func builtImageName(p *project.Project, service project.Service) string {
+	if image := service.Config().Image; image != "" {
+		return image
+	}
+	return fmt.Sprintf("%s_%s", p.Name, service.Name())
+}
+
 func CreateTar(p *project.Project, name string) (io.ReadCloser, error) {
 	// This code was ripped off from docker/api/client/build.go
 
@@ -3,16 +3,15 @@ package docker
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"math"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Sirupsen/logrus"
-	"github.com/docker/docker/cliconfig"
-	"github.com/docker/docker/graph/tags"
-	"github.com/docker/docker/pkg/parsers"
 	"github.com/docker/docker/pkg/stdcopy"
-	"github.com/docker/docker/registry"
-	"github.com/docker/docker/utils"
 	"github.com/docker/libcompose/logger"
 	"github.com/docker/libcompose/project"
 	"github.com/samalba/dockerclient"
@@ -44,37 +43,70 @@ func (c *Container) findInfo() (*dockerclient.ContainerInfo, error) {
 		return nil, err
 	}
 
+	if container == nil {
+		return nil, fmt.Errorf("Container %s not found", c.name)
+	}
+
 	return c.client.InspectContainer(container.Id)
 }
 
 func (c *Container) Info() (project.Info, error) {
 	container, err := c.findExisting()
 	if err != nil {
-		return nil, err
+		return project.Info{}, err
 	}
 
-	result := project.Info{}
+	if container == nil {
+		return project.Info{}, fmt.Errorf("Container %s not found", c.name)
+	}
 
-	result = append(result, project.InfoPart{Key: "Name", Value: name(container.Names)})
-	result = append(result, project.InfoPart{Key: "Command", Value: container.Command})
-	result = append(result, project.InfoPart{Key: "State", Value: container.Status})
-	result = append(result, project.InfoPart{Key: "Ports", Value: portString(container.Ports)})
+	info, err := c.client.InspectContainer(container.Id)
+	if err != nil {
+		return project.Info{}, err
+	}
+
+	return project.Info{
+		Id:       container.Id,
+		Name:     name(container.Names),
+		Service:  c.service.name,
+		Image:    container.Image,
+		Command:  container.Command,
+		Created:  time.Unix(container.Created, 0),
+		State:    containerState(info.State),
+		Status:   container.Status,
+		ExitCode: info.State.ExitCode,
+		Health:   c.reportedHealth(info),
+		Ports:    portBindings(container.Ports),
+	}, nil
+}
 
-	return result, nil
+func containerState(state *dockerclient.State) string {
+	switch {
+	case state.Paused:
+		return project.STATE_PAUSED
+	case state.Restarting:
+		return project.STATE_RESTARTING
+	case state.Running:
+		return project.STATE_RUNNING
+	case state.StartedAt.IsZero():
+		return project.STATE_CREATED
+	}
+	return project.STATE_EXITED
 }
 
-func portString(ports []dockerclient.Port) string {
-	result := []string{}
+func portBindings(ports []dockerclient.Port) []project.PortBinding {
+	result := []project.PortBinding{}
 
 	for _, port := range ports {
-		if port.PublicPort > 0 {
-			result = append(result, fmt.Sprintf("%s:%d->%d/%s", port.IP, port.PublicPort, port.PrivatePort, port.Type))
-		} else {
-			result = append(result, fmt.Sprintf("%d/%s", port.PrivatePort, port.Type))
-		}
+		result = append(result, project.PortBinding{
+			HostIp:        port.IP,
+			HostPort:      port.PublicPort,
+			ContainerPort: port.PrivatePort,
+			Protocol:      port.Type,
+		})
 	}
 
-	return strings.Join(result, ", ")
+	return result
 }
 
 func name(names []string) string {
@@ -122,6 +154,26 @@ func (c *Container) Kill() error {
 	})
 }
 
+// Pause pauses the container if it is running.
+func (c *Container) Pause() error {
+	info, err := c.findInfo()
+	if err != nil || !info.State.Running || info.State.Paused {
+		return err
+	}
+
+	return c.client.PauseContainer(info.Id)
+}
+
+// Unpause unpauses the container if it is paused.
+func (c *Container) Unpause() error {
+	info, err := c.findInfo()
+	if err != nil || !info.State.Paused {
+		return err
+	}
+
+	return c.client.UnpauseContainer(info.Id)
+}
+
 func (c *Container) Delete() error {
 	container, err := c.findExisting()
 	if err != nil || container == nil {
@@ -140,7 +192,7 @@ func (c *Container) Delete() error {
 		}
 	}
 
-	return c.client.RemoveContainer(container.Id, true, false)
+	return c.client.RemoveContainer(container.Id, true, c.service.context.RemoveVolumes)
 }
 
 func (c *Container) Up(imageName string) error {
@@ -148,7 +200,10 @@ func (c *Container) Up(imageName string) error {
 
 	defer func() {
 		if err == nil && c.service.context.Log {
-			go c.Log()
+			go c.log(project.LogOptions{
+				Follow:     true,
+				Timestamps: c.service.context.LogOptions.Timestamps,
+			})
 		}
 	}()
 
@@ -195,7 +250,107 @@ func (c *Container) OutOfSync() (bool, error) {
 	return info.Config.Labels[HASH.Str()] != project.GetServiceHash(c.service), nil
 }
 
+// imageChanged returns whether the container was created from another image
+// than the specified one.
+func (c *Container) imageChanged(imageId string) (bool, error) {
+	container, err := c.findExisting()
+	if err != nil || container == nil {
+		return false, err
+	}
+
+	info, err := c.client.InspectContainer(container.Id)
+	if err != nil {
+		return false, err
+	}
+
+	return info.Image != imageId, nil
+}
+
+// recreate replaces the container with a new one created from the image,
+// which mounts the volumes of the previous container. The previous container
+// is renamed while the new one is created, then removed.
+func (c *Container) recreate(imageName string) error {
+	container, err := c.findExisting()
+	if err != nil || container == nil {
+		return err
+	}
+
+	info, err := c.client.InspectContainer(container.Id)
+	if err != nil {
+		return err
+	}
+
+	details, err := c.Inspect()
+	if err != nil {
+		return err
+	}
+
+	if info.State.Running {
+		if err := c.client.StopContainer(container.Id, c.service.context.Timeout); err != nil {
+			return err
+		}
+	}
+
+	if err := c.client.RenameContainer(container.Id, fmt.Sprintf("%s_%s", container.Id[:12], c.name)); err != nil {
+		return err
+	}
+
+	config, err := c.containerConfig(imageName)
+	if err != nil {
+		return err
+	}
+	config.HostConfig.Binds = append(config.HostConfig.Binds, volumeBinds(details.Mounts, config.HostConfig.Binds)...)
+
+	if _, err := c.createFromConfig(config); err != nil {
+		return err
+	}
+
+	c.service.context.Project.Notify(project.CONTAINER_CREATED, c.service.Name(), map[string]string{
+		"name": c.Name(),
+	})
+
+	return c.client.RemoveContainer(container.Id, true, false)
+}
+
+// volumeBinds returns the binds that mount the volumes at the same
+// destinations, except the destinations that are already bound. Daemons
+// before 1.9 don't name volumes, so the volumes can't be carried over.
+func volumeBinds(mounts []project.Mount, binds []string) []string {
+	bound := map[string]bool{}
+	for _, bind := range binds {
+		if parts := strings.Split(bind, ":"); len(parts) > 1 {
+			bound[parts[1]] = true
+		}
+	}
+
+	result := []string{}
+	for _, mount := range mounts {
+		if mount.Name == "" || bound[mount.Destination] {
+			continue
+		}
+
+		bind := mount.Name + ":" + mount.Destination
+		if !mount.ReadWrite {
+			bind += ":ro"
+		}
+		result = append(result, bind)
+	}
+
+	return result
+}
+
 func (c *Container) createContainer(imageName string) (*dockerclient.Container, error) {
+	config, err := c.containerConfig(imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.createFromConfig(config)
+}
+
+// containerConfig returns the configuration of the container, from the
+// configuration of its service.
+func (c *Container) containerConfig(imageName string) (*dockerclient.ContainerConfig, error) {
 	config, err := ConvertToApi(c.service.serviceConfig)
 	if err != nil {
 		return nil, err
@@ -217,20 +372,18 @@ func (c *Container) createContainer(imageName string) (*dockerclient.Container,
 		return nil, err
 	}
 
+	return config, nil
+}
+
+func (c *Container) createFromConfig(config *dockerclient.ContainerConfig) (*dockerclient.Container, error) {
 	logrus.Debugf("Creating container %s %#v", c.name, config)
 
-	_, err = c.client.CreateContainer(config, c.name)
-	if err != nil && err.Error() == "Not found" {
-		logrus.Debugf("Not Found, pulling image %s", config.Image)
-		if err = c.pull(config.Image); err != nil {
-			return nil, err
-		}
-		if _, err = c.client.CreateContainer(config, c.name); err != nil {
-			return nil, err
-		}
+	create := c.client.CreateContainer
+	if c.service.Config().HealthCheck.Enabled() && apiVersionAtLeast(c.client, healthCheckAPIVersion) {
+		create = c.createWithHealthCheck
 	}
 
-	if err != nil {
+	if _, err := create(config, c.name); err != nil {
 		logrus.Debugf("Failed to create container %s: %v", c.name, err)
 		return nil, err
 	}
@@ -332,7 +485,7 @@ func (c *Container) Name() string {
 }
 
 func (c *Container) Pull() error {
-	return c.pull(c.service.serviceConfig.Image)
+	return c.service.pull(c.client, c.service.context.ImageOf(c.service.name, c.service.serviceConfig))
 }
 
 func (c *Container) Restart() error {
@@ -344,7 +497,13 @@ func (c *Container) Restart() error {
 	return c.client.RestartContainer(container.Id, c.service.context.Timeout)
 }
 
+// Log prints the logs of the container, as defined by the log options of
+// the context.
 func (c *Container) Log() error {
+	return c.log(c.service.context.LogOptions)
+}
+
+func (c *Container) log(options project.LogOptions) error {
 	container, err := c.findExisting()
 	if container == nil || err != nil {
 		return err
@@ -357,15 +516,11 @@ func (c *Container) Log() error {
 
 	l := c.service.context.LoggerFactory.Create(c.name)
 
-	output, err := c.client.ContainerLogs(container.Id, &dockerclient.LogOptions{
-		Follow: true,
-		Stdout: true,
-		Stderr: true,
-		Tail:   10,
-	})
+	output, err := c.logs(container.Id, options)
 	if err != nil {
 		return err
 	}
+	defer output.Close()
 
 	if info.Config.Tty {
 		scanner := bufio.NewScanner(output)
@@ -384,33 +539,29 @@ func (c *Container) Log() error {
 	}
 }
 
-func (c *Container) pull(image string) error {
-	taglessRemote, tag := parsers.ParseRepositoryTag(image)
-	if tag == "" {
-		image = utils.ImageReference(taglessRemote, tags.DEFAULTTAG)
-	}
-
-	repoInfo, err := registry.ParseRepositoryInfo(taglessRemote)
-	if err != nil {
-		return err
-	}
-
-	authConfig := cliconfig.AuthConfig{}
-	if c.service.context.ConfigFile != nil && repoInfo != nil && repoInfo.Index != nil {
-		authConfig = registry.ResolveAuthConfig(c.service.context.ConfigFile, repoInfo.Index)
+func (c *Container) logs(id string, options project.LogOptions) (io.ReadCloser, error) {
+	if options.Since.IsZero() {
+		return c.client.ContainerLogs(id, &dockerclient.LogOptions{
+			Follow:     options.Follow,
+			Stdout:     true,
+			Stderr:     true,
+			Timestamps: options.Timestamps,
+			Tail:       int64(options.Tail),
+		})
 	}
 
-	err = c.client.PullImage(image, &dockerclient.AuthConfig{
-		Username: authConfig.Username,
-		Password: authConfig.Password,
-		Email:    authConfig.Email,
-	})
-
-	if err != nil {
-		logrus.Errorf("Failed to pull image %s: %v", image, err)
+	// dockerclient doesn't support the since parameter
+	query := url.Values{}
+	query.Set("follow", strconv.FormatBool(options.Follow))
+	query.Set("stdout", "true")
+	query.Set("stderr", "true")
+	query.Set("timestamps", strconv.FormatBool(options.Timestamps))
+	query.Set("since", strconv.FormatInt(options.Since.Unix(), 10))
+	if options.Tail > 0 {
+		query.Set("tail", strconv.Itoa(options.Tail))
 	}
 
-	return err
+	return apiStream(c.client, "GET", fmt.Sprintf("/containers/%s/logs?%s", id, query.Encode()), nil, nil)
 }
 
 func (c *Container) withContainer(action func(*dockerclient.Container) error) error {
@@ -443,3 +594,38 @@ func (c *Container) Port(port string) (string, error) {
 		return "", nil
 	}
 }
+
+// Wait implements project.Container.Wait. It uses the wait endpoint of the
+// Docker API, and polls the state of the container with clients that don't
+// support it.
+func (c *Container) Wait() (int, error) {
+	container, err := c.findExisting()
+	if err != nil {
+		return 0, err
+	}
+	if container == nil {
+		return 0, fmt.Errorf("Container %s not found", c.name)
+	}
+
+	var result struct {
+		StatusCode int
+	}
+
+	err = apiJSON(c.client, "POST", fmt.Sprintf("/containers/%s/wait", container.Id), nil, &result)
+	if err != project.ErrUnsupported {
+		return result.StatusCode, err
+	}
+
+	for {
+		info, err := c.client.InspectContainer(container.Id)
+		if err != nil {
+			return 0, err
+		}
+
+		if !info.State.Running && !info.State.Restarting {
+			return info.State.ExitCode, nil
+		}
+
+		time.Sleep(conditionPollInterval)
+	}
+}
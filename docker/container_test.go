@@ -0,0 +1,20 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeBinds(t *testing.T) {
+	mounts := []project.Mount{
+		{Name: "abc", Source: "/var/lib/docker/volumes/abc/_data", Destination: "/data", ReadWrite: true},
+		{Name: "def", Source: "/var/lib/docker/volumes/def/_data", Destination: "/config"},
+		{Name: "ghi", Source: "/var/lib/docker/volumes/ghi/_data", Destination: "/logs", ReadWrite: true},
+		{Source: "/tmp", Destination: "/tmp", ReadWrite: true},
+	}
+
+	binds := volumeBinds(mounts, []string{"/var/log:/logs"})
+	assert.Equal(t, []string{"abc:/data", "def:/config:ro"}, binds)
+}
@@ -11,6 +11,11 @@ type Context struct {
 	ClientFactory ClientFactory
 	ConfigDir     string
 	ConfigFile    *cliconfig.ConfigFile
+	// AuthLookup resolves the registry credentials of pulls, builds and
+	// pushes. It is loaded from ConfigDir if not set.
+	AuthLookup *AuthLookup
+	// startEvents is shared by the services that follow logs.
+	startEvents eventStream
 }
 
 func (c *Context) open() error {
@@ -18,16 +23,31 @@ func (c *Context) open() error {
 }
 
 func (c *Context) LookupConfig() error {
+	if c.AuthLookup != nil {
+		return nil
+	}
+
 	if c.ConfigFile != nil {
+		c.AuthLookup = NewAuthLookup(c.ConfigFile)
 		return nil
 	}
 
-	config, err := cliconfig.Load(c.ConfigDir)
+	lookup, err := LoadAuthLookup(c.ConfigDir)
 	if err != nil {
 		return err
 	}
 
-	c.ConfigFile = config
+	c.AuthLookup = lookup
+	c.ConfigFile = lookup.configFile
 
 	return nil
 }
+
+// authLookup returns the AuthLookup of the context, or one for its config
+// file if the config wasn't looked up.
+func (c *Context) authLookup() *AuthLookup {
+	if c.AuthLookup != nil {
+		return c.AuthLookup
+	}
+	return NewAuthLookup(c.ConfigFile)
+}
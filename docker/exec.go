@@ -0,0 +1,91 @@
+package docker
+
+import (
+	"fmt"
+
+	"github.com/docker/libcompose/project"
+)
+
+type apiExecConfig struct {
+	User         string
+	AttachStdin  bool
+	AttachStdout bool
+	AttachStderr bool
+	Tty          bool
+	Cmd          []string
+}
+
+// Exec implements project.Container.Exec. Unlike dockerclient.Exec, it
+// attaches the standard streams and returns the exit code of the command.
+func (c *Container) Exec(options project.ExecOptions) (int, error) {
+	info, err := c.findInfo()
+	if err != nil {
+		return 0, err
+	}
+
+	if !info.State.Running {
+		return 0, fmt.Errorf("Container %s is not running", c.name)
+	}
+
+	var created struct {
+		Id string
+	}
+
+	err = apiJSON(c.client, "POST", fmt.Sprintf("/containers/%s/exec", info.Id), &apiExecConfig{
+		User:         options.User,
+		AttachStdin:  options.Stdin != nil,
+		AttachStdout: options.Stdout != nil,
+		AttachStderr: options.Stderr != nil,
+		Tty:          options.Tty,
+		Cmd:          options.Command,
+	}, &created)
+	if err != nil {
+		return 0, err
+	}
+
+	stream, err := apiHijack(c.client, "POST", fmt.Sprintf("/exec/%s/start", created.Id), map[string]bool{
+		"Detach": false,
+		"Tty":    options.Tty,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer stream.Close()
+
+	c.pipe(stream, options.Tty, options.Stdin, options.Stdout, options.Stderr)
+
+	var inspect apiExecInspect
+	if err := apiJSON(c.client, "GET", fmt.Sprintf("/exec/%s/json", created.Id), nil, &inspect); err != nil {
+		return 0, err
+	}
+
+	return inspect.ExitCode, nil
+}
+
+// Exec runs a command in the container of the service with the specified
+// index, starting at 1, and returns its exit code.
+func (s *Service) Exec(index int, options project.ExecOptions) (int, error) {
+	containers, err := s.collectContainers()
+	if err != nil {
+		return 0, err
+	}
+
+	container := containerWithIndex(containers, index)
+	if container == nil {
+		return 0, fmt.Errorf("Service %s has no container with index %d", s.name, index)
+	}
+
+	return container.Exec(options)
+}
+
+// containerWithIndex returns the container with the specified index, or nil.
+// The order of the containers is the one of the daemon, which doesn't follow
+// their index.
+func containerWithIndex(containers []*Container, index int) *Container {
+	for _, container := range containers {
+		if container.index() == index {
+			return container
+		}
+	}
+	return nil
+}
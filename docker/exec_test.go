@@ -0,0 +1,87 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/samalba/dockerclient"
+	"github.com/stretchr/testify/assert"
+)
+
+// newExecDaemon returns a daemon with one running container, whose exec
+// commands echo their input back and exit with the code 3.
+func newExecDaemon(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/v1.15/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]dockerclient.Container{{Id: "abc"}})
+	})
+	mux.HandleFunc("/v1.15/containers/abc/json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&dockerclient.ContainerInfo{
+			Id:    "abc",
+			State: &dockerclient.State{Running: true},
+		})
+	})
+	mux.HandleFunc("/v1.15/containers/abc/exec", func(w http.ResponseWriter, r *http.Request) {
+		var config apiExecConfig
+		json.NewDecoder(r.Body).Decode(&config)
+		assert.Equal(t, []string{"cat"}, config.Cmd)
+		assert.True(t, config.AttachStdin)
+
+		w.Write([]byte(`{"Id":"def"}`))
+	})
+	mux.HandleFunc("/v1.15/exec/def/start", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, `{"Detach":false,"Tty":true}`, string(body))
+
+		conn, buffer, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		buffer.WriteString("HTTP/1.1 101 UPGRADED\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+		buffer.Flush()
+
+		// The command only ends once its input is closed
+		input, err := ioutil.ReadAll(buffer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn.Write(input)
+	})
+	mux.HandleFunc("/v1.15/exec/def/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ExitCode":3}`))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+func TestExecSendsStdin(t *testing.T) {
+	server := newExecDaemon(t)
+	defer server.Close()
+
+	client, err := dockerclient.NewDockerClient(server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := newIndexService("")
+	stdout := &bytes.Buffer{}
+
+	exitCode, err := NewContainer(client, "myproject_web_1", service).Exec(project.ExecOptions{
+		Command: []string{"cat"},
+		Tty:     true,
+		Stdin:   strings.NewReader("hello\n"),
+		Stdout:  stdout,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, exitCode)
+	assert.Equal(t, "hello\n", stdout.String())
+}
@@ -0,0 +1,199 @@
+package docker
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/libcompose/project"
+	"github.com/samalba/dockerclient"
+)
+
+// healthCheckAPIVersion is the first API version that supports health checks.
+const healthCheckAPIVersion = "1.24"
+
+type apiHealthConfig struct {
+	Test        []string
+	Interval    int64
+	Timeout     int64
+	Retries     int
+	StartPeriod int64 `json:",omitempty"`
+}
+
+type apiExecInspect struct {
+	Running  bool
+	ExitCode int
+}
+
+type apiContainerHealth struct {
+	State struct {
+		Health *struct {
+			Status string
+		}
+	}
+}
+
+func convertHealthCheck(healthCheck *project.HealthCheck) (*apiHealthConfig, error) {
+	interval, err := healthCheck.IntervalDuration()
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := healthCheck.TimeoutDuration()
+	if err != nil {
+		return nil, err
+	}
+	startPeriod, err := healthCheck.StartPeriodDuration()
+	if err != nil {
+		return nil, err
+	}
+
+	return &apiHealthConfig{
+		Test:        healthCheck.Command(),
+		Interval:    int64(interval),
+		Timeout:     int64(timeout),
+		Retries:     healthCheck.RetriesCount(),
+		StartPeriod: int64(startPeriod),
+	}, nil
+}
+
+// createWithHealthCheck creates the container with its health check, which
+// dockerclient.ContainerConfig can't hold, so that Docker runs it.
+func (c *Container) createWithHealthCheck(config *dockerclient.ContainerConfig, name string) (string, error) {
+	healthCheck, err := convertHealthCheck(c.service.Config().HealthCheck)
+	if err != nil {
+		return "", err
+	}
+
+	body := struct {
+		*dockerclient.ContainerConfig
+		Healthcheck *apiHealthConfig
+	}{config, healthCheck}
+
+	var result dockerclient.RespContainersCreate
+	err = apiJSON(c.client, "POST", fmt.Sprintf("/v%s/containers/create?name=%s", healthCheckAPIVersion, url.QueryEscape(name)), body, &result)
+	return result.Id, err
+}
+
+// Health returns the health status of the container: starting, healthy or
+// unhealthy. It returns an empty string if the service has no health check.
+// The status reported by Docker is used if Docker runs the health check,
+// otherwise the check is run once through exec.
+func (c *Container) Health() (string, error) {
+	healthCheck := c.service.Config().HealthCheck
+	if !healthCheck.Enabled() {
+		return "", nil
+	}
+
+	info, err := c.findInfo()
+	if err != nil {
+		return "", err
+	}
+
+	if !info.State.Running {
+		return project.HEALTH_UNHEALTHY, nil
+	}
+
+	if health, err := c.daemonHealth(info.Id); err != nil || health != "" {
+		return health, err
+	}
+
+	return c.execHealth(info, healthCheck)
+}
+
+// execHealth runs the health check of the running container once through
+// exec and returns its health status. The container is starting while the
+// check fails within the start period.
+func (c *Container) execHealth(info *dockerclient.ContainerInfo, healthCheck *project.HealthCheck) (string, error) {
+	timeout, err := healthCheck.TimeoutDuration()
+	if err != nil {
+		return "", err
+	}
+
+	healthy, err := c.execCheck(info.Id, healthCheck.Exec(), timeout)
+	if err != nil {
+		return "", err
+	}
+
+	if healthy {
+		return project.HEALTH_HEALTHY, nil
+	}
+
+	startPeriod, err := healthCheck.StartPeriodDuration()
+	if err != nil {
+		return "", err
+	}
+
+	if time.Since(info.State.StartedAt) < startPeriod {
+		return project.HEALTH_STARTING, nil
+	}
+
+	return project.HEALTH_UNHEALTHY, nil
+}
+
+// execCheck runs the specified command in the container and returns whether
+// it exited successfully before the timeout.
+func (c *Container) execCheck(id string, command []string, timeout time.Duration) (bool, error) {
+	result := make(chan error, 1)
+	var execID string
+
+	go func() {
+		var err error
+		execID, err = c.client.Exec(&dockerclient.ExecConfig{
+			AttachStdout: true,
+			AttachStderr: true,
+			Cmd:          command,
+			Container:    id,
+		})
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			return false, err
+		}
+	case <-time.After(timeout):
+		logrus.Debugf("Command %v in %s timed out after %v", command, c.name, timeout)
+		return false, nil
+	}
+
+	var inspect apiExecInspect
+	if err := apiJSON(c.client, "GET", fmt.Sprintf("/exec/%s/json", execID), nil, &inspect); err != nil {
+		return false, err
+	}
+
+	logrus.Debugf("Command %v in %s exited with code %d", command, c.name, inspect.ExitCode)
+	return !inspect.Running && inspect.ExitCode == 0, nil
+}
+
+// daemonHealth returns the health status Docker reports for the container,
+// or an empty string if Docker doesn't run its health check.
+func (c *Container) daemonHealth(id string) (string, error) {
+	if !apiVersionAtLeast(c.client, healthCheckAPIVersion) {
+		return "", nil
+	}
+
+	var health apiContainerHealth
+	err := apiJSON(c.client, "GET", fmt.Sprintf("/v%s/containers/%s/json", healthCheckAPIVersion, id), nil, &health)
+	if err != nil || health.State.Health == nil {
+		return "", err
+	}
+	return health.State.Health.Status, nil
+}
+
+// reportedHealth returns the health status Docker reports for the running
+// container, for listings. Unlike Health, it never runs the health check
+// and never fails.
+func (c *Container) reportedHealth(info *dockerclient.ContainerInfo) string {
+	if !c.service.Config().HealthCheck.Enabled() || !info.State.Running {
+		return ""
+	}
+
+	health, err := c.daemonHealth(info.Id)
+	if err != nil {
+		logrus.Debugf("Failed to get the health of %s: %v", c.name, err)
+		return ""
+	}
+	return health
+}
@@ -0,0 +1,103 @@
+package docker
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/docker/libcompose/project"
+	"github.com/samalba/dockerclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertHealthCheck(t *testing.T) {
+	healthCheck, err := convertHealthCheck(&project.HealthCheck{
+		Test:     project.NewStringorslice("redis-cli ping"),
+		Interval: "5s",
+		Timeout:  "1s",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"CMD-SHELL", "redis-cli ping"}, healthCheck.Test)
+	assert.Equal(t, int64(5*time.Second), healthCheck.Interval)
+	assert.Equal(t, int64(time.Second), healthCheck.Timeout)
+	assert.Equal(t, 3, healthCheck.Retries)
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("1.24", "1.24"))
+	assert.Equal(t, 1, compareVersions("1.100", "1.24"))
+	assert.Equal(t, -1, compareVersions("1.15", "1.24"))
+	assert.Equal(t, 1, compareVersions("2", "1.24"))
+}
+
+// versionClient is a Docker client that only reports its version, and
+// fails the test if anything else is called.
+type versionClient struct {
+	dockerclient.Client
+	version string
+	calls   int
+}
+
+func (c *versionClient) Version() (*dockerclient.Version, error) {
+	c.calls++
+	return &dockerclient.Version{ApiVersion: c.version}, nil
+}
+
+func TestReportedHealth(t *testing.T) {
+	client := &versionClient{version: "1.15"}
+	c := NewContainer(client, "project_db_1", &Service{
+		name: "db",
+		serviceConfig: &project.ServiceConfig{
+			HealthCheck: &project.HealthCheck{Test: project.NewStringorslice("pg_isready")},
+		},
+	})
+
+	stopped := &dockerclient.ContainerInfo{Id: "abc", State: &dockerclient.State{}}
+	assert.Equal(t, "", c.reportedHealth(stopped))
+	assert.Equal(t, 0, client.calls)
+
+	// Older daemons don't run the health check, which isn't run through exec
+	running := &dockerclient.ContainerInfo{Id: "abc", State: &dockerclient.State{Running: true}}
+	assert.Equal(t, "", c.reportedHealth(running))
+	assert.Equal(t, 1, client.calls)
+}
+
+func TestConditionMetFailsOnReportedUnhealthy(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1.15/version", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ApiVersion":"1.24"}`))
+	})
+	mux.HandleFunc("/v1.15/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]dockerclient.Container{{
+			Id:     "abc",
+			Labels: map[string]string{NAME.Str(): "myproject_web_1"},
+		}})
+	})
+	mux.HandleFunc("/v1.15/containers/abc/json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&dockerclient.ContainerInfo{
+			Id:    "abc",
+			State: &dockerclient.State{Running: true},
+		})
+	})
+	mux.HandleFunc("/v1.24/containers/abc/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"State":{"Health":{"Status":"unhealthy"}}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := dockerclient.NewDockerClient(server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := newIndexService("")
+	service.serviceConfig.HealthCheck = &project.HealthCheck{Test: project.NewStringorslice("pg_isready")}
+	service.context.ClientFactory = &defaultClientFactory{client: client}
+
+	// Docker already retried its health check, so it fails at once
+	met, err := service.conditionMet(project.CONDITION_HEALTHY, map[string]int{})
+	assert.False(t, met)
+	assert.NotNil(t, err)
+}
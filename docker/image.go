@@ -0,0 +1,258 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/graph/tags"
+	"github.com/docker/docker/pkg/parsers"
+	"github.com/docker/docker/utils"
+	"github.com/docker/libcompose/project"
+	"github.com/samalba/dockerclient"
+)
+
+// Digest implements project.Service.Digest. It asks the daemon for the
+// digest of the image in its registry, and falls back to the digest of the
+// local image for the daemons that don't support it.
+func (s *Service) Digest() (string, error) {
+	image := s.serviceConfig.Image
+	if image == "" {
+		return "", fmt.Errorf("Service %s has no image", s.name)
+	}
+
+	client := s.context.ClientFactory.Create(s)
+
+	digest, err := s.registryDigest(client, image)
+	if err == nil && digest != "" {
+		return digest, nil
+	}
+	logrus.Debugf("Failed to get the digest of %s from its registry, using the local image: %v", image, err)
+
+	return localDigest(client, image)
+}
+
+func (s *Service) registryDigest(client dockerclient.Client, image string) (string, error) {
+	repository, _ := parsers.ParseRepositoryTag(image)
+	auth, err := s.authConfig(repository)
+	if err != nil {
+		return "", err
+	}
+
+	encodedAuth, err := encodeAuth(auth)
+	if err != nil {
+		return "", err
+	}
+
+	stream, err := apiStream(client, "GET", "/distribution/"+image+"/json", nil, map[string]string{
+		"X-Registry-Auth": encodedAuth,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	var distribution struct {
+		Descriptor struct {
+			Digest string
+		}
+	}
+	if err := json.NewDecoder(stream).Decode(&distribution); err != nil {
+		return "", err
+	}
+
+	return distribution.Descriptor.Digest, nil
+}
+
+// localDigest returns the digest of the local image from the repository of
+// the image reference.
+func localDigest(client dockerclient.Client, image string) (string, error) {
+	var inspect struct {
+		RepoDigests []string
+	}
+	if err := apiJSON(client, "GET", "/images/"+image+"/json", nil, &inspect); err != nil {
+		return "", err
+	}
+
+	repository, _ := parsers.ParseRepositoryTag(image)
+	for _, repoDigest := range inspect.RepoDigests {
+		if digestRepository, digest := parsers.ParseRepositoryTag(repoDigest); sameRepository(digestRepository, repository) {
+			return digest, nil
+		}
+	}
+
+	return "", fmt.Errorf("Image %s has no digest, it must be pulled from or pushed to a registry first", image)
+}
+
+// sameRepository returns whether both repositories are the same, the
+// default registry and namespace being implicit, like nginx and
+// docker.io/library/nginx.
+func sameRepository(left, right string) bool {
+	normalize := func(repository string) string {
+		for _, prefix := range []string{"docker.io/", "index.docker.io/", "library/"} {
+			repository = strings.TrimPrefix(repository, prefix)
+		}
+		return repository
+	}
+	return normalize(left) == normalize(right)
+}
+
+// Push implements project.Service.Push. It pushes the image the service is
+// built as, with the credentials of its registry.
+func (s *Service) Push() error {
+	image := s.serviceConfig.Image
+	if s.serviceConfig.Build == "" || image == "" {
+		return nil
+	}
+
+	repository, tag := parsers.ParseRepositoryTag(image)
+	auth, err := s.authConfig(repository)
+	if err != nil {
+		return err
+	}
+
+	encodedAuth, err := encodeAuth(auth)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	if tag != "" {
+		query.Set("tag", tag)
+	}
+
+	client := s.context.ClientFactory.Create(s)
+	stream, err := apiStream(client, "POST", fmt.Sprintf("/images/%s/push?%s", repository, query.Encode()), nil, map[string]string{
+		"X-Registry-Auth": encodedAuth,
+	})
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	err = decodeProgress(stream, func(data map[string]string) {
+		data[project.PULL_IMAGE] = image
+		s.context.Project.Notify(project.SERVICE_PUSH_PROGRESS, s.name, data)
+	})
+	if err != nil {
+		logrus.Errorf("Failed to push image %s: %v", image, err)
+	}
+
+	return err
+}
+
+// ensureImage makes sure that the image of the service exists locally,
+// pulling it as defined by the pull policy of the service.
+func (s *Service) ensureImage(client dockerclient.Client, image string) error {
+	policy, err := s.context.PullPolicyOf(s.name, s.serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	if policy == project.PULL_POLICY_ALWAYS {
+		return s.pull(client, image)
+	}
+
+	_, err = client.InspectImage(image)
+	if err != dockerclient.ErrNotFound {
+		return err
+	}
+
+	if policy == project.PULL_POLICY_NEVER {
+		return fmt.Errorf("Image %s of service %s doesn't exist and its pull policy is never", image, s.name)
+	}
+
+	logrus.Infof("Image %s of service %s doesn't exist, pulling it", image, s.name)
+	return s.pull(client, image)
+}
+
+// builtImageAvailable returns whether the image of a service that is built
+// exists locally once pulled as defined by the pull policy, so that it
+// doesn't need to be built. Only the services with an image and an explicit
+// pull policy are pulled, and pull failures fall back to building the image.
+func (s *Service) builtImageAvailable(client dockerclient.Client, image string, policy project.PullPolicy) (bool, error) {
+	pullable := s.serviceConfig.Image != "" && policy != project.PULL_POLICY_DEFAULT
+
+	if policy == project.PULL_POLICY_ALWAYS && pullable {
+		return s.pullBuiltImage(client, image), nil
+	}
+
+	_, err := client.InspectImage(image)
+	if err == nil {
+		return true, nil
+	} else if err != dockerclient.ErrNotFound {
+		return false, err
+	}
+
+	if policy == project.PULL_POLICY_NEVER || !pullable {
+		return false, nil
+	}
+
+	return s.pullBuiltImage(client, image), nil
+}
+
+func (s *Service) pullBuiltImage(client dockerclient.Client, image string) bool {
+	if err := s.pull(client, image); err != nil {
+		logrus.Warnf("Failed to pull image %s of service %s, building it: %v", image, s.name, err)
+		return false
+	}
+	return true
+}
+
+func (s *Service) pull(client dockerclient.Client, image string) error {
+	taglessRemote, tag := parsers.ParseRepositoryTag(image)
+	if tag == "" {
+		tag = tags.DEFAULTTAG
+		image = utils.ImageReference(taglessRemote, tag)
+	}
+
+	auth, err := s.authConfig(taglessRemote)
+	if err != nil {
+		return err
+	}
+
+	err = s.pullWithProgress(client, image, taglessRemote, tag, auth)
+	if err == project.ErrUnsupported {
+		err = client.PullImage(image, auth)
+	}
+
+	if err != nil {
+		logrus.Errorf("Failed to pull image %s: %v", image, err)
+	}
+
+	return err
+}
+
+// authConfig returns the credentials of the registry of the repository.
+func (s *Service) authConfig(repository string) (*dockerclient.AuthConfig, error) {
+	return s.context.authLookup().Lookup(repository)
+}
+
+// pullWithProgress pulls the image and publishes the status of each of its
+// layers as SERVICE_PULL_PROGRESS events, which dockerclient doesn't
+// support.
+func (s *Service) pullWithProgress(client dockerclient.Client, image, repository, tag string, auth *dockerclient.AuthConfig) error {
+	encodedAuth, err := encodeAuth(auth)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("fromImage", repository)
+	query.Set("tag", tag)
+
+	stream, err := apiStream(client, "POST", "/images/create?"+query.Encode(), nil, map[string]string{
+		"X-Registry-Auth": encodedAuth,
+	})
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	return decodeProgress(stream, func(data map[string]string) {
+		data[project.PULL_IMAGE] = image
+		s.context.Project.Notify(project.SERVICE_PULL_PROGRESS, s.name, data)
+	})
+}
@@ -0,0 +1,145 @@
+package docker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/samalba/dockerclient"
+	"github.com/stretchr/testify/assert"
+)
+
+// imageClient is a Docker client that only knows about images.
+type imageClient struct {
+	dockerclient.Client
+	images []string
+	pulled []string
+	// pullErr makes the pulls fail.
+	pullErr error
+}
+
+func (c *imageClient) InspectImage(id string) (*dockerclient.ImageInfo, error) {
+	for _, image := range c.images {
+		if image == id {
+			return &dockerclient.ImageInfo{Id: id}, nil
+		}
+	}
+	return nil, dockerclient.ErrNotFound
+}
+
+func (c *imageClient) PullImage(name string, auth *dockerclient.AuthConfig) error {
+	if c.pullErr != nil {
+		return c.pullErr
+	}
+	c.pulled = append(c.pulled, name)
+	return nil
+}
+
+func ensureImage(policy project.PullPolicy, images ...string) ([]string, error) {
+	context := &Context{}
+	context.Project = project.NewProject(&context.Context)
+
+	service := &Service{
+		name: "web",
+		serviceConfig: &project.ServiceConfig{
+			Image:      "nginx:latest",
+			PullPolicy: policy,
+		},
+		context: context,
+	}
+
+	client := &imageClient{images: images}
+	err := service.ensureImage(client, "nginx:latest")
+	return client.pulled, err
+}
+
+func TestEnsureImage(t *testing.T) {
+	pulled, err := ensureImage(project.PULL_POLICY_DEFAULT)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"nginx:latest"}, pulled)
+
+	pulled, err = ensureImage(project.PULL_POLICY_MISSING, "nginx:latest")
+	assert.Nil(t, err)
+	assert.Empty(t, pulled)
+
+	pulled, err = ensureImage(project.PULL_POLICY_ALWAYS, "nginx:latest")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"nginx:latest"}, pulled)
+
+	pulled, err = ensureImage(project.PULL_POLICY_NEVER)
+	assert.NotNil(t, err)
+	assert.Empty(t, pulled)
+}
+
+func builtImageAvailable(t *testing.T, policy project.PullPolicy, image string, client *imageClient) bool {
+	context := &Context{}
+	context.Project = project.NewProject(&context.Context)
+
+	service := &Service{
+		name: "web",
+		serviceConfig: &project.ServiceConfig{
+			Build: ".",
+			Image: image,
+		},
+		context: context,
+	}
+
+	available, err := service.builtImageAvailable(client, "myorg/web:latest", policy)
+	assert.Nil(t, err)
+	return available
+}
+
+func TestBuiltImageAvailable(t *testing.T) {
+	client := &imageClient{images: []string{"myorg/web:latest"}}
+	assert.True(t, builtImageAvailable(t, project.PULL_POLICY_MISSING, "myorg/web:latest", client))
+	assert.Empty(t, client.pulled)
+
+	client = &imageClient{}
+	assert.True(t, builtImageAvailable(t, project.PULL_POLICY_MISSING, "myorg/web:latest", client))
+	assert.Equal(t, []string{"myorg/web:latest"}, client.pulled)
+
+	client = &imageClient{images: []string{"myorg/web:latest"}}
+	assert.True(t, builtImageAvailable(t, project.PULL_POLICY_ALWAYS, "myorg/web:latest", client))
+	assert.Equal(t, []string{"myorg/web:latest"}, client.pulled)
+
+	client = &imageClient{pullErr: errors.New("not found")}
+	assert.False(t, builtImageAvailable(t, project.PULL_POLICY_ALWAYS, "myorg/web:latest", client))
+
+	client = &imageClient{}
+	assert.False(t, builtImageAvailable(t, project.PULL_POLICY_NEVER, "myorg/web:latest", client))
+	assert.Empty(t, client.pulled)
+
+	// Services are built unless their pull policy is set
+	client = &imageClient{}
+	assert.False(t, builtImageAvailable(t, project.PULL_POLICY_DEFAULT, "myorg/web:latest", client))
+	assert.Empty(t, client.pulled)
+
+	client = &imageClient{images: []string{"myorg/web:latest"}}
+	assert.True(t, builtImageAvailable(t, project.PULL_POLICY_DEFAULT, "myorg/web:latest", client))
+	assert.Empty(t, client.pulled)
+
+	// Services without image can't be pulled
+	client = &imageClient{}
+	assert.False(t, builtImageAvailable(t, project.PULL_POLICY_ALWAYS, "", client))
+	assert.Empty(t, client.pulled)
+}
+
+func TestSameRepository(t *testing.T) {
+	assert.True(t, sameRepository("nginx", "docker.io/library/nginx"))
+	assert.True(t, sameRepository("library/nginx", "nginx"))
+	assert.True(t, sameRepository("localhost:5000/app", "localhost:5000/app"))
+	assert.False(t, sameRepository("nginx", "myorg/nginx"))
+}
+
+func TestBuiltImageName(t *testing.T) {
+	p := &project.Project{Name: "myproject"}
+
+	assert.Equal(t, "myproject_web", builtImageName(p, &Service{
+		name:          "web",
+		serviceConfig: &project.ServiceConfig{Build: "."},
+	}))
+	assert.Equal(t, "myorg/web:1.0", builtImageName(p, &Service{
+		name:          "web",
+		serviceConfig: &project.ServiceConfig{Build: ".", Image: "myorg/web:1.0"},
+	}))
+}
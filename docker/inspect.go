@@ -0,0 +1,177 @@
+package docker
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/docker/libcompose/project"
+	"github.com/samalba/dockerclient"
+)
+
+// apiContainerInspect holds the fields of a container that dockerclient
+// doesn't decode.
+type apiContainerInspect struct {
+	Mounts []struct {
+		Name        string
+		Source      string
+		Destination string
+		RW          bool
+	}
+	NetworkSettings struct {
+		Networks map[string]struct {
+			IPAddress string
+		}
+	}
+}
+
+// Inspect implements project.Container.Inspect.
+func (c *Container) Inspect() (*project.ContainerDetails, error) {
+	info, err := c.findInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	details := &project.ContainerDetails{
+		Id:          info.Id,
+		Name:        c.name,
+		Service:     c.service.name,
+		Index:       c.index(),
+		State:       containerState(info.State),
+		ExitCode:    info.State.ExitCode,
+		StartedAt:   info.State.StartedAt,
+		FinishedAt:  info.State.FinishedAt,
+		IPAddresses: map[string]string{},
+		Ports:       inspectPorts(info),
+		Mounts:      []project.Mount{},
+	}
+
+	if info.Config != nil {
+		details.Labels = info.Config.Labels
+	}
+
+	var extra apiContainerInspect
+	err = apiJSON(c.client, "GET", fmt.Sprintf("/containers/%s/json", info.Id), nil, &extra)
+	if err != nil && err != project.ErrUnsupported {
+		return nil, err
+	}
+
+	for _, mount := range extra.Mounts {
+		details.Mounts = append(details.Mounts, project.Mount{
+			Name:        mount.Name,
+			Source:      mount.Source,
+			Destination: mount.Destination,
+			ReadWrite:   mount.RW,
+		})
+	}
+
+	for network, settings := range extra.NetworkSettings.Networks {
+		details.IPAddresses[network] = settings.IPAddress
+	}
+
+	// Older daemons only report the volumes and the address on the default network
+	if len(extra.Mounts) == 0 {
+		for destination, source := range info.Volumes {
+			details.Mounts = append(details.Mounts, project.Mount{
+				Source:      source,
+				Destination: destination,
+				ReadWrite:   !readOnlyBind(info.HostConfig, destination),
+			})
+		}
+		sort.Sort(mountsByDestination(details.Mounts))
+	}
+	if len(details.IPAddresses) == 0 && info.NetworkSettings.IPAddress != "" {
+		details.IPAddresses["bridge"] = info.NetworkSettings.IPAddress
+	}
+
+	return details, nil
+}
+
+// readOnlyBind returns whether the volume mounted at destination was bound
+// with the ro mode.
+func readOnlyBind(hostConfig *dockerclient.HostConfig, destination string) bool {
+	if hostConfig == nil {
+		return false
+	}
+	for _, bind := range hostConfig.Binds {
+		parts := strings.Split(bind, ":")
+		if len(parts) == 3 && parts[1] == destination && parts[2] == "ro" {
+			return true
+		}
+	}
+	return false
+}
+
+type mountsByDestination []project.Mount
+
+func (m mountsByDestination) Len() int           { return len(m) }
+func (m mountsByDestination) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m mountsByDestination) Less(i, j int) bool { return m[i].Destination < m[j].Destination }
+
+// inspectPorts returns the exposed ports of the container, with one binding
+// per address a port is published on.
+func inspectPorts(info *dockerclient.ContainerInfo) []project.PortBinding {
+	keys := []string{}
+	for key := range info.NetworkSettings.Ports {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := []project.PortBinding{}
+	for _, key := range keys {
+		parts := strings.SplitN(key, "/", 2)
+		port, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
+		}
+
+		protocol := "tcp"
+		if len(parts) == 2 {
+			protocol = parts[1]
+		}
+
+		bindings := info.NetworkSettings.Ports[key]
+		if len(bindings) == 0 {
+			result = append(result, project.PortBinding{
+				ContainerPort: port,
+				Protocol:      protocol,
+			})
+			continue
+		}
+
+		for _, binding := range bindings {
+			hostPort, _ := strconv.Atoi(binding.HostPort)
+			result = append(result, project.PortBinding{
+				HostIp:        binding.HostIp,
+				HostPort:      hostPort,
+				ContainerPort: port,
+				Protocol:      protocol,
+			})
+		}
+	}
+
+	return result
+}
+
+// index returns the index of the container within its service, from the
+// number its name ends with, like 2 for project_web_2 or 1 for
+// project_web_run_1. The container of a service with a custom name, which
+// can only have one, has the index 1. Other names have the index 0.
+func (c *Container) index() int {
+	if name := c.service.serviceConfig.ContainerName; name != "" && c.name == name {
+		return 1
+	}
+
+	prefix := fmt.Sprintf("%s_%s_", c.service.context.Project.Name, c.service.name)
+	if !strings.HasPrefix(c.name, prefix) {
+		return 0
+	}
+
+	suffix := strings.TrimPrefix(strings.TrimPrefix(c.name, prefix), "run_")
+	value, err := strconv.Atoi(suffix)
+	if err != nil || value < 0 {
+		return 0
+	}
+	return value
+}
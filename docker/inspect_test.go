@@ -0,0 +1,76 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/samalba/dockerclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func newIndexService(containerName string) *Service {
+	context := &Context{}
+	context.Project = &project.Project{Name: "myproject"}
+
+	return &Service{
+		name:          "web",
+		serviceConfig: &project.ServiceConfig{ContainerName: containerName},
+		context:       context,
+	}
+}
+
+func TestContainerIndex(t *testing.T) {
+	service := newIndexService("")
+	assert.Equal(t, 2, NewContainer(nil, "myproject_web_2", service).index())
+	assert.Equal(t, 1, NewContainer(nil, "myproject_web_run_1", service).index())
+	assert.Equal(t, 0, NewContainer(nil, "myproject_webapp_1", service).index())
+	assert.Equal(t, 0, NewContainer(nil, "custom", service).index())
+
+	// A custom name is the single container of its service, whatever it ends with
+	service = newIndexService("my_db_2")
+	assert.Equal(t, 1, NewContainer(nil, "my_db_2", service).index())
+}
+
+func TestContainerWithIndex(t *testing.T) {
+	service := newIndexService("")
+	containers := []*Container{
+		NewContainer(nil, "myproject_web_3", service),
+		NewContainer(nil, "myproject_web_1", service),
+	}
+
+	assert.Equal(t, "myproject_web_1", containerWithIndex(containers, 1).Name())
+	assert.Equal(t, "myproject_web_3", containerWithIndex(containers, 3).Name())
+	assert.Nil(t, containerWithIndex(containers, 2))
+
+	service = newIndexService("my_db_2")
+	containers = []*Container{NewContainer(nil, "my_db_2", service)}
+	assert.Equal(t, "my_db_2", containerWithIndex(containers, 1).Name())
+	assert.Nil(t, containerWithIndex(containers, 2))
+}
+
+func TestInspectPorts(t *testing.T) {
+	info := &dockerclient.ContainerInfo{}
+	info.NetworkSettings.Ports = map[string][]dockerclient.PortBinding{
+		"80/tcp": {
+			{HostIp: "0.0.0.0", HostPort: "8080"},
+			{HostIp: "127.0.0.1", HostPort: "8081"},
+		},
+		"53/udp": nil,
+	}
+
+	assert.Equal(t, []project.PortBinding{
+		{ContainerPort: 53, Protocol: "udp"},
+		{HostIp: "0.0.0.0", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+		{HostIp: "127.0.0.1", HostPort: 8081, ContainerPort: 80, Protocol: "tcp"},
+	}, inspectPorts(info))
+}
+
+func TestReadOnlyBind(t *testing.T) {
+	hostConfig := &dockerclient.HostConfig{
+		Binds: []string{"/src:/data:ro", "/logs:/var/log"},
+	}
+
+	assert.True(t, readOnlyBind(hostConfig, "/data"))
+	assert.False(t, readOnlyBind(hostConfig, "/var/log"))
+	assert.False(t, readOnlyBind(nil, "/data"))
+}
@@ -13,6 +13,7 @@ const (
 	PROJECT = Label("io.docker.compose.project")
 	SERVICE = Label("io.docker.compose.service")
 	HASH    = Label("io.docker.compose.config-hash")
+	ONEOFF  = Label("io.docker.compose.oneoff")
 )
 
 func (f Label) Eq(value string) string {
@@ -0,0 +1,205 @@
+package docker
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/libcompose/project"
+	"github.com/samalba/dockerclient"
+)
+
+// logFollower keeps track of the log streams of a service, one per start
+// of a container.
+type logFollower struct {
+	lock     sync.Mutex
+	attached map[string]bool
+	wg       sync.WaitGroup
+}
+
+// attach follows the logs of the container, unless they are already followed
+// since its last start.
+func (l *logFollower) attach(c *Container, info *dockerclient.ContainerInfo, options project.LogOptions) {
+	key := fmt.Sprintf("%s@%d", info.Id, info.State.StartedAt.UnixNano())
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.attached[key] {
+		return
+	}
+	l.attached[key] = true
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		if err := c.log(options); err != nil {
+			logrus.Errorf("Failed to follow the logs of %s: %v", c.Name(), err)
+		}
+	}()
+}
+
+// followLogs follows the logs of the containers of the service, and of the
+// containers of the service that are started afterwards, as reported by the
+// Docker events. Containers started afterwards are followed from their start.
+func (s *Service) followLogs(options project.LogOptions) error {
+	client := s.context.ClientFactory.Create(s)
+	follower := &logFollower{
+		attached: map[string]bool{},
+	}
+
+	events, unsubscribe, err := s.context.startEvents.subscribe(client)
+	if err != nil {
+		return err
+	}
+	defer unsubscribe()
+
+	containers, err := s.collectContainers()
+	if err != nil {
+		return err
+	}
+
+	for _, c := range containers {
+		info, err := c.findInfo()
+		if err != nil {
+			return err
+		}
+		if info.State.Running {
+			follower.attach(c, info, options)
+		}
+	}
+
+	for event := range events {
+		if event.Error != nil {
+			return event.Error
+		}
+
+		c, info, err := s.startedContainer(client, event.Id)
+		if err != nil {
+			logrus.Debugf("Failed to inspect started container %s: %v", event.Id, err)
+			continue
+		}
+		if c == nil {
+			continue
+		}
+
+		restarted := options
+		restarted.Tail = 0
+		restarted.Since = info.State.StartedAt
+		follower.attach(c, info, restarted)
+	}
+
+	follower.wg.Wait()
+	return nil
+}
+
+// startedContainer returns the container with the specified id if it belongs
+// to the service.
+func (s *Service) startedContainer(client dockerclient.Client, id string) (*Container, *dockerclient.ContainerInfo, error) {
+	info, err := client.InspectContainer(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	labels := info.Config.Labels
+	if labels[PROJECT.Str()] != s.context.Project.Name || labels[SERVICE.Str()] != s.name || labels[ONEOFF.Str()] == "True" {
+		return nil, nil, nil
+	}
+
+	return NewContainer(client, labels[NAME.Str()], s), info, nil
+}
+
+// eventStream shares one stream of the Docker start events between the
+// services of a project. The stream is opened by the first subscriber and
+// closed when the last one leaves.
+type eventStream struct {
+	lock      sync.Mutex
+	broadcast *eventBroadcast
+}
+
+type eventBroadcast struct {
+	subscribers map[*eventSubscriber]bool
+	stop        chan struct{}
+}
+
+type eventSubscriber struct {
+	events chan dockerclient.EventOrError
+	done   chan struct{}
+}
+
+// subscribe returns the start events from now on, and the function to call
+// once they are no longer read. The events channel is closed when the stream
+// ends.
+func (e *eventStream) subscribe(client dockerclient.Client) (<-chan dockerclient.EventOrError, func(), error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.broadcast == nil {
+		stop := make(chan struct{})
+		events, err := client.MonitorEvents(&dockerclient.MonitorEventsOptions{
+			Filters: &dockerclient.MonitorEventsFilters{
+				Event: "start",
+			},
+		}, stop)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		e.broadcast = &eventBroadcast{
+			subscribers: map[*eventSubscriber]bool{},
+			stop:        stop,
+		}
+		go e.forward(e.broadcast, events)
+	}
+
+	broadcast := e.broadcast
+	subscriber := &eventSubscriber{
+		events: make(chan dockerclient.EventOrError),
+		done:   make(chan struct{}),
+	}
+	broadcast.subscribers[subscriber] = true
+
+	return subscriber.events, func() {
+		e.unsubscribe(broadcast, subscriber)
+	}, nil
+}
+
+// forward sends the events of the stream to its subscribers, and closes their
+// channels when the stream ends.
+func (e *eventStream) forward(broadcast *eventBroadcast, events <-chan dockerclient.EventOrError) {
+	for event := range events {
+		e.lock.Lock()
+		for subscriber := range broadcast.subscribers {
+			select {
+			case subscriber.events <- event:
+			case <-subscriber.done:
+			}
+		}
+		e.lock.Unlock()
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for subscriber := range broadcast.subscribers {
+		close(subscriber.events)
+	}
+	broadcast.subscribers = map[*eventSubscriber]bool{}
+	if e.broadcast == broadcast {
+		e.broadcast = nil
+	}
+}
+
+func (e *eventStream) unsubscribe(broadcast *eventBroadcast, subscriber *eventSubscriber) {
+	// Unblocks forward if it is sending to the subscriber
+	close(subscriber.done)
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	delete(broadcast.subscribers, subscriber)
+	if len(broadcast.subscribers) == 0 && e.broadcast == broadcast {
+		close(broadcast.stop)
+		e.broadcast = nil
+	}
+}
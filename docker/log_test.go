@@ -0,0 +1,77 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/samalba/dockerclient"
+	"github.com/stretchr/testify/assert"
+)
+
+type eventsClient struct {
+	dockerclient.Client
+	monitored int
+	events    chan dockerclient.EventOrError
+	stop      <-chan struct{}
+}
+
+func (c *eventsClient) MonitorEvents(options *dockerclient.MonitorEventsOptions, stop <-chan struct{}) (<-chan dockerclient.EventOrError, error) {
+	c.monitored++
+	c.stop = stop
+	return c.events, nil
+}
+
+func TestEventStreamIsShared(t *testing.T) {
+	client := &eventsClient{events: make(chan dockerclient.EventOrError)}
+	stream := &eventStream{}
+
+	first, unsubscribeFirst, err := stream.subscribe(client)
+	assert.NoError(t, err)
+	second, unsubscribeSecond, err := stream.subscribe(client)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, client.monitored)
+
+	event := dockerclient.EventOrError{}
+	event.Id = "abc"
+	go func() {
+		client.events <- event
+	}()
+	// The event is sent to the subscribers one after the other, in any order
+	for i := 0; i < 2; i++ {
+		select {
+		case received := <-first:
+			assert.Equal(t, "abc", received.Id)
+			first = nil
+		case received := <-second:
+			assert.Equal(t, "abc", received.Id)
+			second = nil
+		}
+	}
+
+	unsubscribeFirst()
+	select {
+	case <-client.stop:
+		t.Fatal("The stream was stopped while it had a subscriber")
+	default:
+	}
+
+	unsubscribeSecond()
+	<-client.stop
+
+	_, unsubscribe, err := stream.subscribe(client)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, client.monitored)
+	unsubscribe()
+}
+
+func TestEventStreamEnd(t *testing.T) {
+	client := &eventsClient{events: make(chan dockerclient.EventOrError)}
+	stream := &eventStream{}
+
+	events, unsubscribe, err := stream.subscribe(client)
+	assert.NoError(t, err)
+	defer unsubscribe()
+
+	close(client.events)
+	_, ok := <-events
+	assert.False(t, ok)
+}
@@ -0,0 +1,62 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/libcompose/project"
+	"github.com/samalba/dockerclient"
+)
+
+// decodeProgress reads the JSON messages of a pull or push stream and calls
+// publish with the data of each layer status, keyed like the pull events.
+// It returns the error reported in the stream, if any.
+func decodeProgress(stream io.Reader, publish func(data map[string]string)) error {
+	decoder := json.NewDecoder(stream)
+	for {
+		var message jsonmessage.JSONMessage
+		if err := decoder.Decode(&message); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if message.Error != nil {
+			return message.Error
+		}
+		if message.ErrorMessage != "" {
+			return fmt.Errorf("%s", message.ErrorMessage)
+		}
+
+		if message.Status == "" {
+			continue
+		}
+
+		data := map[string]string{
+			project.PULL_STATUS: message.Status,
+		}
+		if message.ID != "" {
+			data[project.PULL_LAYER] = message.ID
+		}
+		if message.Progress != nil && message.Progress.Total > 0 {
+			data[project.PULL_CURRENT] = strconv.Itoa(message.Progress.Current)
+			data[project.PULL_TOTAL] = strconv.Itoa(message.Progress.Total)
+		}
+
+		publish(data)
+	}
+}
+
+// encodeAuth encodes the credentials for the X-Registry-Auth header.
+func encodeAuth(auth *dockerclient.AuthConfig) (string, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(auth); err != nil {
+		return "", err
+	}
+	return base64.URLEncoding.EncodeToString(buf.Bytes()), nil
+}
@@ -0,0 +1,44 @@
+package docker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeProgress(t *testing.T) {
+	stream := strings.NewReader(`{"status":"Pulling from library/nginx","id":"latest"}
+{"status":"Downloading","progressDetail":{"current":512,"total":1024},"id":"a3ed95caeb02"}
+{"status":"Download complete","progressDetail":{},"id":"a3ed95caeb02"}
+{"status":"Digest: sha256:1234"}
+`)
+
+	events := []map[string]string{}
+	err := decodeProgress(stream, func(data map[string]string) {
+		events = append(events, data)
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, events, 4)
+	assert.Equal(t, map[string]string{
+		project.PULL_STATUS:  "Downloading",
+		project.PULL_LAYER:   "a3ed95caeb02",
+		project.PULL_CURRENT: "512",
+		project.PULL_TOTAL:   "1024",
+	}, events[1])
+	assert.Equal(t, map[string]string{
+		project.PULL_STATUS: "Digest: sha256:1234",
+	}, events[3])
+}
+
+func TestDecodeProgressError(t *testing.T) {
+	stream := strings.NewReader(`{"status":"Pulling repository nothing"}
+{"errorDetail":{"message":"Error: image library/nothing not found"},"error":"Error: image library/nothing not found"}
+`)
+
+	err := decodeProgress(stream, func(data map[string]string) {})
+	assert.NotNil(t, err)
+	assert.Equal(t, "Error: image library/nothing not found", err.Error())
+}
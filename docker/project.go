@@ -22,6 +22,12 @@ func NewProject(context *Context) (*project.Project, error) {
 		}
 	}
 
+	if context.Resources == nil {
+		context.Resources = &Resources{
+			context: context,
+		}
+	}
+
 	if context.Builder == nil {
 		context.Builder = NewDaemonBuilder(context)
 	}
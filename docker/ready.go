@@ -0,0 +1,107 @@
+package docker
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/docker/libcompose/project"
+	"github.com/samalba/dockerclient"
+)
+
+// probeReady runs the ready probe once against the container and returns an
+// error describing why the container isn't ready, if it isn't.
+func (c *Container) probeReady(probe *project.ReadyProbe) error {
+	info, err := c.findInfo()
+	if err != nil {
+		return err
+	}
+
+	if !info.State.Running {
+		return fmt.Errorf("Container %s is not running", c.name)
+	}
+
+	timeout, err := probe.TimeoutDuration()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case probe.Tcp > 0:
+		address, err := c.probeAddress(info, probe.Tcp)
+		if err != nil {
+			return err
+		}
+
+		conn, err := net.DialTimeout("tcp", address, timeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case probe.Http != nil:
+		address, err := c.probeAddress(info, probe.Http.Port)
+		if err != nil {
+			return err
+		}
+
+		url := fmt.Sprintf("http://%s/%s", address, strings.TrimPrefix(probe.Http.Path, "/"))
+		resp, err := (&http.Client{Timeout: timeout}).Get(url)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != probe.Http.ExpectedStatus() {
+			return fmt.Errorf("GET %s returned %d, expected %d", url, resp.StatusCode, probe.Http.ExpectedStatus())
+		}
+		return nil
+	default:
+		ok, err := c.execCheck(info.Id, probe.ExecCommand(), timeout)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("Command %v failed", probe.ExecCommand())
+		}
+		return nil
+	}
+}
+
+// probeAddress returns the address to reach the specified port of the
+// container: the published address if the port is published, the address
+// of the container otherwise.
+func (c *Container) probeAddress(info *dockerclient.ContainerInfo, port int) (string, error) {
+	published, err := c.Port(fmt.Sprintf("%d/tcp", port))
+	if err != nil {
+		return "", err
+	}
+
+	if published != "" {
+		host, hostPort, err := net.SplitHostPort(strings.Split(published, "\n")[0])
+		if err != nil {
+			return "", err
+		}
+		if host == "" || host == "0.0.0.0" {
+			host = daemonHost(c.client)
+		}
+		return net.JoinHostPort(host, hostPort), nil
+	}
+
+	if info.NetworkSettings.IPAddress == "" {
+		return "", fmt.Errorf("Container %s has no address for port %d", c.name, port)
+	}
+
+	return net.JoinHostPort(info.NetworkSettings.IPAddress, strconv.Itoa(port)), nil
+}
+
+// daemonHost returns the host the Docker daemon publishes ports on.
+func daemonHost(client dockerclient.Client) string {
+	if dockerClient, ok := client.(*dockerclient.DockerClient); ok {
+		if host, _, err := net.SplitHostPort(dockerClient.URL.Host); err == nil && host != "" {
+			return host
+		}
+	}
+	return "127.0.0.1"
+}
@@ -0,0 +1,89 @@
+package docker
+
+import (
+	"sort"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/libcompose/project"
+	"github.com/docker/libcompose/utils"
+	"github.com/samalba/dockerclient"
+)
+
+// Resources implements project.ProjectResources with the labels of the
+// containers.
+type Resources struct {
+	context *Context
+}
+
+// RemoveOneOffs removes the one-off containers created by Service.Run.
+func (r *Resources) RemoveOneOffs(p *project.Project) error {
+	client := r.context.ClientFactory.Create(nil)
+	containers, err := GetContainersByFilter(client, PROJECT.Eq(p.Name), ONEOFF.Eq("True"))
+	if err != nil {
+		return err
+	}
+
+	return r.remove(client, containers)
+}
+
+// Orphans implements project.ProjectResources.Orphans.
+func (r *Resources) Orphans(p *project.Project) ([]string, error) {
+	orphans, err := r.orphans(p)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, container := range orphans {
+		names = append(names, container.Labels[NAME.Str()])
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// RemoveOrphans removes the containers of the services that are no longer
+// defined in the project.
+func (r *Resources) RemoveOrphans(p *project.Project) error {
+	orphans, err := r.orphans(p)
+	if err != nil {
+		return err
+	}
+
+	return r.remove(r.context.ClientFactory.Create(nil), orphans)
+}
+
+func (r *Resources) orphans(p *project.Project) ([]dockerclient.Container, error) {
+	client := r.context.ClientFactory.Create(nil)
+	containers, err := GetContainersByFilter(client, PROJECT.Eq(p.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	orphans := []dockerclient.Container{}
+	for _, container := range containers {
+		if _, ok := p.Configs[container.Labels[SERVICE.Str()]]; !ok && container.Labels[ONEOFF.Str()] != "True" {
+			orphans = append(orphans, container)
+		}
+	}
+
+	return orphans, nil
+}
+
+func (r *Resources) remove(client dockerclient.Client, containers []dockerclient.Container) error {
+	tasks := utils.NewInParallel(r.context.Parallelism)
+
+	for _, container := range containers {
+		task := func(container dockerclient.Container) func() error {
+			return func() error {
+				name := container.Labels[NAME.Str()]
+				logrus.Infof("Removing %s", name)
+				return utils.ForContainer(name, client.RemoveContainer(container.Id, true, r.context.RemoveVolumes))
+			}
+		}(container)
+
+		tasks.Add(task)
+	}
+
+	return tasks.Wait()
+}
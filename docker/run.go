@@ -0,0 +1,146 @@
+package docker
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/libcompose/project"
+	"github.com/samalba/dockerclient"
+)
+
+// Run implements project.Service.Run. The one-off container is named after
+// the service with a run suffix, labeled as one-off so that it isn't
+// considered a container of the service, and doesn't publish any port.
+func (s *Service) Run(options project.RunOptions) (int, error) {
+	imageName, err := s.build()
+	if err != nil {
+		return 0, err
+	}
+
+	client := s.context.ClientFactory.Create(s)
+	namer := NewNamer(client, s.context.Project.Name, s.name+"_run")
+	containerName := namer.Next()
+	namer.Close()
+
+	c := NewContainer(client, containerName, s)
+	return c.run(imageName, options)
+}
+
+func (c *Container) run(imageName string, options project.RunOptions) (int, error) {
+	config, err := c.containerConfig(imageName)
+	if err != nil {
+		return 0, err
+	}
+
+	applyRunOptions(config, options)
+	config.Labels[ONEOFF.Str()] = "True"
+	config.HostConfig.PortBindings = nil
+
+	container, err := c.createFromConfig(config)
+	if err != nil {
+		return 0, err
+	}
+
+	if options.Remove {
+		defer func() {
+			if err := c.client.RemoveContainer(container.Id, true, false); err != nil {
+				logrus.Errorf("Failed to remove %s: %v", c.name, err)
+			}
+		}()
+	}
+
+	stream, err := apiHijack(c.client, "POST", fmt.Sprintf("/containers/%s/attach?stream=1&stdin=%d&stdout=%d&stderr=%d",
+		container.Id, flag(options.Stdin != nil), flag(options.Stdout != nil), flag(options.Stderr != nil)), nil)
+	if err != nil {
+		return 0, err
+	}
+	defer stream.Close()
+
+	if err := c.client.StartContainer(container.Id, &config.HostConfig); err != nil {
+		return 0, err
+	}
+
+	c.service.context.Project.Notify(project.CONTAINER_STARTED, c.service.Name(), map[string]string{
+		"name": c.Name(),
+	})
+
+	c.pipe(stream, options.Tty, options.Stdin, options.Stdout, options.Stderr)
+
+	return c.Wait()
+}
+
+func applyRunOptions(config *dockerclient.ContainerConfig, options project.RunOptions) {
+	if len(options.Command) > 0 {
+		config.Cmd = options.Command
+	}
+	if len(options.Entrypoint) > 0 {
+		config.Entrypoint = options.Entrypoint
+	}
+	if options.User != "" {
+		config.User = options.User
+	}
+
+	for _, value := range options.Environment {
+		key := strings.SplitN(value, "=", 2)[0]
+		env := []string{}
+		for _, existing := range config.Env {
+			if strings.SplitN(existing, "=", 2)[0] != key {
+				env = append(env, existing)
+			}
+		}
+		config.Env = append(env, value)
+	}
+
+	config.Tty = options.Tty
+	config.AttachStdin = options.Stdin != nil
+	config.OpenStdin = options.Stdin != nil
+	config.StdinOnce = options.Stdin != nil
+	config.AttachStdout = options.Stdout != nil
+	config.AttachStderr = options.Stderr != nil
+}
+
+// pipe sends the input to the stream and copies the output of the stream,
+// demultiplexed unless a TTY is allocated, until the stream ends.
+func (c *Container) pipe(stream io.ReadWriter, tty bool, stdin io.Reader, stdout, stderr io.Writer) {
+	if stdin != nil {
+		go func() {
+			if _, err := io.Copy(stream, stdin); err != nil {
+				logrus.Debugf("Failed to send input to %s: %v", c.name, err)
+			}
+			if closer, ok := stream.(interface {
+				CloseWrite() error
+			}); ok {
+				closer.CloseWrite()
+			}
+		}()
+	}
+
+	if stdout == nil {
+		stdout = ioutil.Discard
+	}
+	if stderr == nil {
+		stderr = ioutil.Discard
+	}
+
+	var err error
+	if tty {
+		_, err = io.Copy(stdout, stream)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, stream)
+	}
+
+	if err != nil {
+		logrus.Debugf("Failed to read output of %s: %v", c.name, err)
+	}
+}
+
+func flag(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
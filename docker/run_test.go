@@ -0,0 +1,35 @@
+package docker
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/docker/libcompose/project"
+	"github.com/samalba/dockerclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyRunOptions(t *testing.T) {
+	config := &dockerclient.ContainerConfig{
+		Cmd:  []string{"web"},
+		Env:  []string{"DEBUG=0", "PORT=80"},
+		User: "app",
+	}
+
+	applyRunOptions(config, project.RunOptions{
+		Command:     []string{"manage.py", "migrate"},
+		Environment: []string{"DEBUG=1"},
+		Tty:         true,
+		Stdin:       &bytes.Buffer{},
+		Stdout:      &bytes.Buffer{},
+	})
+
+	assert.Equal(t, []string{"manage.py", "migrate"}, config.Cmd)
+	assert.Equal(t, []string{"PORT=80", "DEBUG=1"}, config.Env)
+	assert.Equal(t, "app", config.User)
+	assert.Nil(t, config.Entrypoint)
+	assert.True(t, config.Tty)
+	assert.True(t, config.OpenStdin)
+	assert.True(t, config.AttachStdout)
+	assert.False(t, config.AttachStderr)
+}
@@ -1,11 +1,19 @@
 package docker
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/libcompose/project"
 	"github.com/docker/libcompose/utils"
+	"github.com/samalba/dockerclient"
 )
 
+// conditionPollInterval is the interval at which the containers are
+// inspected while waiting for a depends_on condition.
+var conditionPollInterval = time.Second
+
 type Service struct {
 	name          string
 	serviceConfig *project.ServiceConfig
@@ -40,6 +48,9 @@ func (s *Service) collectContainers() ([]*Container, error) {
 	result := []*Container{}
 
 	for _, container := range containers {
+		if container.Labels[ONEOFF.Str()] == "True" {
+			continue
+		}
 		result = append(result, NewContainer(client, container.Labels[NAME.Str()], s))
 	}
 
@@ -55,29 +66,65 @@ func (s *Service) createOne() (*Container, error) {
 	return containers[0], err
 }
 
+// Build implements project.Service.Build. The image is built whatever the
+// pull policy of the service.
 func (s *Service) Build() error {
-	_, err := s.build()
-	return err
+	imageName, err := s.buildImage()
+	if err != nil {
+		return err
+	}
+
+	s.imageName = imageName
+	return nil
 }
 
+// build returns the image of the service for its containers, pulled or
+// built as defined by its pull policy.
 func (s *Service) build() (string, error) {
 	if s.imageName != "" {
 		return s.imageName, nil
 	}
 
-	if s.context.Builder == nil {
-		s.imageName = s.Config().Image
-	} else {
-		var err error
-		s.imageName, err = s.context.Builder.Build(s.context.Project, s)
-		if err != nil {
+	policy, err := s.context.PullPolicyOf(s.name, s.serviceConfig)
+	if err != nil {
+		return "", err
+	}
+
+	client := s.context.ClientFactory.Create(s)
+	imageName := s.context.ImageOf(s.name, s.serviceConfig)
+
+	if s.serviceConfig.Build == "" {
+		if err := s.ensureImage(client, imageName); err != nil {
 			return "", err
 		}
+	} else {
+		imageName = builtImageName(s.context.Project, s)
+
+		available := false
+		if policy != project.PULL_POLICY_BUILD {
+			if available, err = s.builtImageAvailable(client, imageName, policy); err != nil {
+				return "", err
+			}
+		}
+
+		if !available {
+			if imageName, err = s.buildImage(); err != nil {
+				return "", err
+			}
+		}
 	}
 
+	s.imageName = imageName
 	return s.imageName, nil
 }
 
+func (s *Service) buildImage() (string, error) {
+	if s.context.Builder == nil {
+		return s.Config().Image, nil
+	}
+	return s.context.Builder.Build(s.context.Project, s)
+}
+
 func (s *Service) constructContainers(create bool, count int) ([]*Container, error) {
 	result, err := s.collectContainers()
 	if err != nil {
@@ -170,34 +217,68 @@ func (s *Service) up(imageName string, create bool) error {
 		containers = []*Container{c}
 	}
 
+	imageId, err := s.imageId(imageName)
+	if err != nil {
+		return err
+	}
+
 	return s.eachContainer(func(c *Container) error {
 		if outOfSync, err := c.OutOfSync(); err != nil {
 			return err
 		} else if outOfSync {
 			logrus.Warnf("%s needs rebuilding", s.Name())
 		}
+
+		if imageId != "" {
+			if changed, err := c.imageChanged(imageId); err != nil {
+				return err
+			} else if changed {
+				logrus.Infof("Recreating %s, its image changed", c.Name())
+				if err := c.recreate(imageName); err != nil {
+					return err
+				}
+			}
+		}
+
 		return c.Up(imageName)
 	})
 }
 
+// imageId returns the id of the image, or an empty string if no image is
+// specified.
+func (s *Service) imageId(imageName string) (string, error) {
+	if imageName == "" {
+		return "", nil
+	}
+
+	info, err := s.context.ClientFactory.Create(s).InspectImage(imageName)
+	if err != nil {
+		return "", err
+	}
+	return info.Id, nil
+}
+
 func (s *Service) eachContainer(action func(*Container) error) error {
+	return s.inParallel(utils.NewInParallel(s.context.Parallelism), action)
+}
+
+func (s *Service) inParallel(tasks *utils.InParallel, action func(*Container) error) error {
 	containers, err := s.collectContainers()
 	if err != nil {
 		return err
 	}
 
-	tasks := utils.InParallel{}
 	for _, container := range containers {
 		task := func(container *Container) func() error {
 			return func() error {
-				return action(container)
+				return utils.ForContainer(container.Name(), action(container))
 			}
 		}(container)
 
 		tasks.Add(task)
 	}
 
-	return tasks.Wait()
+	return utils.ForService(s.name, tasks.Wait())
 }
 
 func (s *Service) Down() error {
@@ -218,13 +299,55 @@ func (s *Service) Kill() error {
 	})
 }
 
+func (s *Service) Pause() error {
+	return s.eachContainer(func(c *Container) error {
+		return c.Pause()
+	})
+}
+
+func (s *Service) Unpause() error {
+	return s.eachContainer(func(c *Container) error {
+		return c.Unpause()
+	})
+}
+
 func (s *Service) Delete() error {
 	return s.eachContainer(func(c *Container) error {
 		return c.Delete()
 	})
 }
 
+// RemoveImage implements project.Service.RemoveImage. Built images that
+// are tagged with the image of the service are only removed with
+// IMAGE_TYPE_ALL, like pulled images.
+func (s *Service) RemoveImage(imageType project.ImageType) error {
+	var image string
+	if s.Config().Build != "" && (s.Config().Image == "" || imageType == project.IMAGE_TYPE_ALL) {
+		image = builtImageName(s.context.Project, s)
+	} else if imageType == project.IMAGE_TYPE_ALL {
+		image = s.Config().Image
+	}
+
+	if image == "" {
+		return nil
+	}
+
+	client := s.context.ClientFactory.Create(s)
+	if _, err := client.RemoveImage(image); err != nil && err != dockerclient.ErrNotFound {
+		return err
+	}
+
+	logrus.Infof("Removed image %s", image)
+	return nil
+}
+
+// Log implements project.Service.Log. When following, containers that are
+// started afterwards, like new or restarted ones, are followed too.
 func (s *Service) Log() error {
+	if s.context.LogOptions.Follow {
+		return s.followLogs(s.context.LogOptions)
+	}
+
 	return s.eachContainer(func(c *Container) error {
 		return c.Log()
 	})
@@ -261,12 +384,125 @@ func (s *Service) Scale(scale int) error {
 }
 
 func (s *Service) Pull() error {
-	containers, err := s.constructContainers(false, 1)
+	return s.pull(s.context.ClientFactory.Create(s), s.context.ImageOf(s.name, s.serviceConfig))
+}
+
+// WaitFor implements project.Service.WaitFor by polling the state of the
+// containers of the service. A service is healthy when the health check of
+// each container succeeds, or when each container runs if the service has
+// no health check.
+func (s *Service) WaitFor(condition project.DependencyCondition) error {
+	var err error
+	interval := conditionPollInterval
+	healthCheck := s.Config().HealthCheck
+
+	if condition == project.CONDITION_HEALTHY && healthCheck.Enabled() {
+		if interval, err = healthCheck.IntervalDuration(); err != nil {
+			return err
+		}
+	} else if condition == project.CONDITION_READY && s.Config().Ready != nil {
+		if interval, err = s.Config().Ready.IntervalDuration(); err != nil {
+			return err
+		}
+	}
+
+	failures := map[string]int{}
+	for {
+		met, err := s.conditionMet(condition, failures)
+		if err != nil || met {
+			return err
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+func (s *Service) conditionMet(condition project.DependencyCondition, failures map[string]int) (bool, error) {
+	containers, err := s.collectContainers()
 	if err != nil {
-		return err
+		return false, err
+	}
+
+	if len(containers) == 0 {
+		return false, fmt.Errorf("No container found for %s", s.name)
+	}
+
+	met := true
+	for _, c := range containers {
+		info, err := c.findInfo()
+		if err != nil {
+			return false, err
+		}
+
+		state := info.State
+		exited := !state.Running && !state.Restarting
+
+		switch condition {
+		case project.CONDITION_HEALTHY:
+			if exited {
+				return false, fmt.Errorf("Container %s exited with code %d", c.Name(), state.ExitCode)
+			}
+			if !state.Running {
+				met = false
+				continue
+			}
+
+			healthCheck := s.Config().HealthCheck
+			if !healthCheck.Enabled() {
+				continue
+			}
+
+			// Docker retries its health check before reporting unhealthy
+			health, err := c.daemonHealth(info.Id)
+			if err != nil {
+				return false, err
+			}
+			reported := health != ""
+			if !reported {
+				if health, err = c.execHealth(info, healthCheck); err != nil {
+					return false, err
+				}
+			}
+
+			switch {
+			case health == project.HEALTH_UNHEALTHY && reported:
+				return false, fmt.Errorf("Container %s is unhealthy", c.Name())
+			case health == project.HEALTH_UNHEALTHY:
+				failures[c.Name()]++
+				if failures[c.Name()] >= healthCheck.RetriesCount() {
+					return false, fmt.Errorf("Container %s is unhealthy", c.Name())
+				}
+				met = false
+			case health == project.HEALTH_STARTING:
+				met = false
+			default:
+				failures[c.Name()] = 0
+			}
+		case project.CONDITION_READY:
+			if exited {
+				return false, fmt.Errorf("Container %s exited with code %d", c.Name(), state.ExitCode)
+			}
+
+			if err := c.probeReady(s.Config().Ready); err != nil {
+				logrus.Debugf("Container %s is not ready: %v", c.Name(), err)
+				failures[c.Name()]++
+				if failures[c.Name()] >= s.Config().Ready.RetriesCount() {
+					return false, fmt.Errorf("Container %s is not ready: %v", c.Name(), err)
+				}
+				met = false
+			}
+		case project.CONDITION_COMPLETED_SUCCESSFULLY:
+			if !exited {
+				met = false
+				continue
+			}
+			if state.ExitCode != 0 {
+				return false, fmt.Errorf("Container %s exited with code %d", c.Name(), state.ExitCode)
+			}
+		}
 	}
 
-	return containers[0].Pull()
+	return met, nil
 }
 
 func (s *Service) Containers() ([]project.Container, error) {
@@ -158,8 +158,7 @@ func (s *RunSuite) TestDown(c *C) {
 	s.FromText(c, p, "down", SimpleTemplate)
 
 	cn = s.GetContainerByName(c, name)
-	c.Assert(cn, NotNil)
-	c.Assert(cn.State.Running, Equals, false)
+	c.Assert(cn, IsNil)
 }
 
 func (s *RunSuite) TestLink(c *C) {
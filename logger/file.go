@@ -0,0 +1,178 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+)
+
+const (
+	// DefaultMaxSize is the size in bytes after which FileLogger rotates
+	// its file by default.
+	DefaultMaxSize = 10 * 1024 * 1024
+	// DefaultMaxFiles is the number of rotated files FileLogger keeps by
+	// default.
+	DefaultMaxFiles = 5
+)
+
+// FileLoggerFactory implements Factory interface using FileLogger. Loggers
+// of the same name share one FileLogger, so that a file is only opened and
+// rotated once.
+type FileLoggerFactory struct {
+	dir      string
+	maxSize  int64
+	maxFiles int
+	lock     sync.Mutex
+	loggers  map[string]*FileLogger
+}
+
+// FileLogger implements Logger interface by appending the output to a file
+// per container, named after the container in the directory of the factory.
+// Once the file reaches the maximum size, it is renamed with a .1 suffix,
+// the previous .1 file with a .2 suffix and so on.
+type FileLogger struct {
+	lock    sync.Mutex
+	path    string
+	file    *os.File
+	size    int64
+	factory *FileLoggerFactory
+}
+
+// NewFileLoggerFactory creates a new FileLoggerFactory writing to the
+// specified directory. A maxSize of zero disables rotation.
+func NewFileLoggerFactory(dir string, maxSize int64, maxFiles int) *FileLoggerFactory {
+	return &FileLoggerFactory{
+		dir:      dir,
+		maxSize:  maxSize,
+		maxFiles: maxFiles,
+		loggers:  map[string]*FileLogger{},
+	}
+}
+
+// Create implements Factory.Create.
+func (f *FileLoggerFactory) Create(name string) Logger {
+	path := filepath.Join(f.dir, name+".log")
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if l, ok := f.loggers[path]; ok {
+		return l
+	}
+
+	l := &FileLogger{
+		path:    path,
+		factory: f,
+	}
+	f.loggers[path] = l
+	return l
+}
+
+// Close closes the files of the loggers. They are opened again if the
+// loggers are written to afterwards.
+func (f *FileLoggerFactory) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	var result error
+	for _, l := range f.loggers {
+		if err := l.close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// Out implements Logger.Out.
+func (f *FileLogger) Out(bytes []byte) {
+	f.write(bytes)
+}
+
+// Err implements Logger.Err.
+func (f *FileLogger) Err(bytes []byte) {
+	f.write(bytes)
+}
+
+func (f *FileLogger) write(bytes []byte) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.file != nil && f.factory.maxSize > 0 && f.size+int64(len(bytes)) > f.factory.maxSize {
+		if err := f.rotate(); err != nil {
+			logrus.Errorf("Failed to rotate %s: %v", f.path, err)
+		}
+	}
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			logrus.Errorf("Failed to open %s: %v", f.path, err)
+			return
+		}
+	}
+
+	n, err := f.file.Write(bytes)
+	f.size += int64(n)
+	if err != nil {
+		logrus.Errorf("Failed to write to %s: %v", f.path, err)
+	}
+}
+
+func (f *FileLogger) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+func (f *FileLogger) close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+func (f *FileLogger) rotate() error {
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		return err
+	}
+
+	if f.factory.maxFiles < 1 {
+		return os.Remove(f.path)
+	}
+
+	for i := f.factory.maxFiles - 1; i > 0; i-- {
+		previous := fmt.Sprintf("%s.%d", f.path, i)
+		if _, err := os.Stat(previous); err == nil {
+			if err := os.Rename(previous, fmt.Sprintf("%s.%d", f.path, i+1)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return os.Rename(f.path, f.path+".1")
+}
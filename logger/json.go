@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JsonLoggerFactory implements Factory interface using JsonLogger.
+type JsonLoggerFactory struct {
+	lock    sync.Mutex
+	encoder *json.Encoder
+}
+
+// JsonLogger implements Logger interface by writing each line of output as
+// a JSON object.
+type JsonLogger struct {
+	service   string
+	container string
+	factory   *JsonLoggerFactory
+}
+
+// JsonLine is the JSON object written by JsonLogger for each line of output.
+type JsonLine struct {
+	Time      time.Time `json:"time"`
+	Service   string    `json:"service"`
+	Container string    `json:"container"`
+	Stream    string    `json:"stream"`
+	Message   string    `json:"message"`
+}
+
+// NewJsonLoggerFactory creates a new JsonLoggerFactory writing to the
+// specified writer.
+func NewJsonLoggerFactory(writer io.Writer) *JsonLoggerFactory {
+	return &JsonLoggerFactory{
+		encoder: json.NewEncoder(writer),
+	}
+}
+
+// Create implements Factory.Create.
+func (j *JsonLoggerFactory) Create(name string) Logger {
+	return &JsonLogger{
+		service:   ServiceName(name),
+		container: name,
+		factory:   j,
+	}
+}
+
+// Out implements Logger.Out.
+func (j *JsonLogger) Out(bytes []byte) {
+	j.write("stdout", bytes)
+}
+
+// Err implements Logger.Err.
+func (j *JsonLogger) Err(bytes []byte) {
+	j.write("stderr", bytes)
+}
+
+func (j *JsonLogger) write(stream string, bytes []byte) {
+	j.factory.lock.Lock()
+	defer j.factory.lock.Unlock()
+
+	now := time.Now().UTC()
+	for _, line := range strings.Split(strings.TrimRight(string(bytes), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+
+		j.factory.encoder.Encode(&JsonLine{
+			Time:      now,
+			Service:   j.service,
+			Container: j.container,
+			Stream:    stream,
+			Message:   strings.TrimSuffix(line, "\r"),
+		})
+	}
+}
+
+// ServiceName returns the name of the service from the name of a logger,
+// which is the name of the service followed by the index of the container,
+// like web_1.
+func ServiceName(name string) string {
+	index := strings.LastIndex(name, "_")
+	if index <= 0 || strings.Trim(name[index+1:], "0123456789") != "" || index == len(name)-1 {
+		return name
+	}
+	return name[:index]
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceName(t *testing.T) {
+	assert.Equal(t, "web", ServiceName("web_1"))
+	assert.Equal(t, "api_web", ServiceName("api_web_12"))
+	assert.Equal(t, "custom", ServiceName("custom"))
+	assert.Equal(t, "web_", ServiceName("web_"))
+	assert.Equal(t, "web_a", ServiceName("web_a"))
+}
+
+func TestJsonLogger(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	l := NewJsonLoggerFactory(buffer).Create("web_1")
+
+	l.Out([]byte("first\nsecond\n"))
+	l.Err([]byte("failed\n"))
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Equal(t, 3, len(lines))
+
+	var line JsonLine
+	assert.Nil(t, json.Unmarshal([]byte(lines[2]), &line))
+	assert.Equal(t, "web", line.Service)
+	assert.Equal(t, "web_1", line.Container)
+	assert.Equal(t, "stderr", line.Stream)
+	assert.Equal(t, "failed", line.Message)
+}
+
+func TestFileLoggerRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := NewFileLoggerFactory(dir, 10, 2).Create("web_1")
+	for _, line := range []string{"aaaaaaaa\n", "bbbbbbbb\n", "cccccccc\n", "dddddddd\n"} {
+		l.Out([]byte(line))
+	}
+
+	path := filepath.Join(dir, "web_1.log")
+	for suffix, expected := range map[string]string{"": "dddddddd\n", ".1": "cccccccc\n", ".2": "bbbbbbbb\n"} {
+		content, err := ioutil.ReadFile(path + suffix)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, string(content))
+	}
+
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFileLoggerIsSharedPerName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "logger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	factory := NewFileLoggerFactory(dir, 10, 2)
+	first := factory.Create("web_1")
+	second := factory.Create("web_1")
+	assert.True(t, first == second)
+
+	first.Out([]byte("aaaaaaaa\n"))
+	second.Out([]byte("bbbbbbbb\n"))
+	assert.Nil(t, factory.Close())
+
+	path := filepath.Join(dir, "web_1.log")
+	for suffix, expected := range map[string]string{"": "bbbbbbbb\n", ".1": "aaaaaaaa\n"} {
+		content, err := ioutil.ReadFile(path + suffix)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, string(content))
+	}
+}
+
+func TestMultiLogger(t *testing.T) {
+	first, second := &bytes.Buffer{}, &bytes.Buffer{}
+	l := NewMultiLoggerFactory(NewJsonLoggerFactory(first), NewJsonLoggerFactory(second)).Create("db_1")
+
+	l.Out([]byte("ready\n"))
+
+	assert.Contains(t, first.String(), `"message":"ready"`)
+	assert.Contains(t, second.String(), `"message":"ready"`)
+}
@@ -0,0 +1,43 @@
+package logger
+
+// MultiLoggerFactory implements Factory interface using MultiLogger.
+type MultiLoggerFactory struct {
+	factories []Factory
+}
+
+// MultiLogger implements Logger interface by sending the output to several
+// loggers.
+type MultiLogger struct {
+	loggers []Logger
+}
+
+// NewMultiLoggerFactory creates a new MultiLoggerFactory combining the
+// specified factories.
+func NewMultiLoggerFactory(factories ...Factory) *MultiLoggerFactory {
+	return &MultiLoggerFactory{
+		factories: factories,
+	}
+}
+
+// Create implements Factory.Create.
+func (m *MultiLoggerFactory) Create(name string) Logger {
+	logger := &MultiLogger{}
+	for _, factory := range m.factories {
+		logger.loggers = append(logger.loggers, factory.Create(name))
+	}
+	return logger
+}
+
+// Out implements Logger.Out.
+func (m *MultiLogger) Out(bytes []byte) {
+	for _, logger := range m.loggers {
+		logger.Out(bytes)
+	}
+}
+
+// Err implements Logger.Err.
+func (m *MultiLogger) Err(bytes []byte) {
+	for _, logger := range m.loggers {
+		logger.Err(bytes)
+	}
+}
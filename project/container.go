@@ -0,0 +1,60 @@
+package project
+
+import (
+	"time"
+)
+
+// ContainerDetails describes the current state and configuration of a
+// container, as returned by Container.Inspect.
+type ContainerDetails struct {
+	Id      string
+	Name    string
+	Service string
+	// Index is the number of the container within its service, starting at
+	// 1. The container of a service with a custom name has the index 1.
+	Index int
+	// State is one of the STATE_ constants.
+	State      string
+	ExitCode   int
+	StartedAt  time.Time
+	FinishedAt time.Time
+	// IPAddresses maps the name of each network the container is connected
+	// to to its IP address on that network.
+	IPAddresses map[string]string
+	Ports       []PortBinding
+	Mounts      []Mount
+	Labels      map[string]string
+}
+
+// Mount is a volume or a bind mount of a container.
+type Mount struct {
+	// Name is the name of the volume, empty for a bind mount.
+	Name        string
+	Source      string
+	Destination string
+	ReadWrite   bool
+}
+
+// IPAddress returns the IP address of the container, on the first of its
+// networks by name if it is connected to several.
+func (d *ContainerDetails) IPAddress() string {
+	var first string
+	for network, address := range d.IPAddresses {
+		if address != "" && (first == "" || network < first) {
+			first = network
+		}
+	}
+	return d.IPAddresses[first]
+}
+
+// Published returns the host port bindings of the specified container port
+// and protocol.
+func (d *ContainerDetails) Published(port int, protocol string) []PortBinding {
+	result := []PortBinding{}
+	for _, binding := range d.Ports {
+		if binding.ContainerPort == port && binding.Protocol == protocol && binding.HostPort > 0 {
+			result = append(result, binding)
+		}
+	}
+	return result
+}
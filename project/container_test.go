@@ -0,0 +1,34 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerDetailsIPAddress(t *testing.T) {
+	details := &ContainerDetails{
+		IPAddresses: map[string]string{
+			"myproject_front": "172.18.0.2",
+			"myproject_back":  "172.19.0.2",
+		},
+	}
+	assert.Equal(t, "172.19.0.2", details.IPAddress())
+
+	details.IPAddresses = nil
+	assert.Equal(t, "", details.IPAddress())
+}
+
+func TestContainerDetailsPublished(t *testing.T) {
+	details := &ContainerDetails{
+		Ports: []PortBinding{
+			{HostIp: "0.0.0.0", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+			{ContainerPort: 443, Protocol: "tcp"},
+			{HostIp: "0.0.0.0", HostPort: 5353, ContainerPort: 53, Protocol: "udp"},
+		},
+	}
+
+	assert.Equal(t, []PortBinding{{HostIp: "0.0.0.0", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"}}, details.Published(80, "tcp"))
+	assert.Empty(t, details.Published(443, "tcp"))
+	assert.Empty(t, details.Published(53, "tcp"))
+}
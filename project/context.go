@@ -18,6 +18,7 @@ var projectRegexp = regexp.MustCompile("[^a-zA-Z0-9_.-]")
 type Context struct {
 	Timeout             int
 	Log                 bool
+	LogOptions          LogOptions
 	Signal              string
 	ComposeFile         string
 	ComposeBytes        []byte
@@ -29,6 +30,30 @@ type Context struct {
 	LoggerFactory       logger.Factory
 	IgnoreMissingConfig bool
 	Project             *Project
+	// Parallelism limits how many services, and how many containers of a
+	// service, are operated on at the same time. Zero means no limit.
+	Parallelism int
+	// Selection overrides which services, besides the named ones, project
+	// operations apply to.
+	Selection SelectionMode
+	// RemoveVolumes removes the anonymous volumes of the containers that are
+	// deleted.
+	RemoveVolumes bool
+	// PullPolicy overrides the pull policy of every service when set.
+	PullPolicy PullPolicy
+	// LockFile is the path of the image lock file, next to the compose file
+	// by default.
+	LockFile string
+	// EnforceLock makes Up and Pull fail if a service isn't locked with its
+	// image or if its tag moved, and use the images pinned by the lock file.
+	EnforceLock bool
+	imageLock   *ImageLock
+	// IgnorePullFailures makes Pull log the images that fail to pull and
+	// carry on, instead of returning an error.
+	IgnorePullFailures bool
+	// Resources manages the containers of the project that don't belong to
+	// one of its services. It is optional.
+	Resources ProjectResources
 }
 
 func (c *Context) readComposeFile() error {
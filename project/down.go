@@ -0,0 +1,93 @@
+package project
+
+import (
+	"fmt"
+)
+
+// ImageType defines which images Teardown removes.
+type ImageType string
+
+const (
+	IMAGE_TYPE_NONE  = ImageType("")
+	IMAGE_TYPE_LOCAL = ImageType("local")
+	IMAGE_TYPE_ALL   = ImageType("all")
+)
+
+// ParseImageType parses the value of the --rmi flag.
+func ParseImageType(value string) (ImageType, error) {
+	switch ImageType(value) {
+	case IMAGE_TYPE_NONE, IMAGE_TYPE_LOCAL, IMAGE_TYPE_ALL:
+		return ImageType(value), nil
+	}
+	return IMAGE_TYPE_NONE, fmt.Errorf("Invalid image type %s, expected local or all", value)
+}
+
+// ProjectResources manages the containers of a project that don't belong
+// to one of its services: one-off containers, and the containers of
+// services that were removed from the compose file.
+type ProjectResources interface {
+	// Orphans returns the names of the containers of the services that are
+	// no longer defined in the project.
+	Orphans(p *Project) ([]string, error)
+	RemoveOneOffs(p *Project) error
+	RemoveOrphans(p *Project) error
+}
+
+// DownOptions holds the settings of Teardown.
+type DownOptions struct {
+	RemoveImages  ImageType
+	RemoveOrphans bool
+}
+
+// Teardown stops the services in reverse dependency order, then removes
+// their containers, the one-off containers and optionally the images and
+// the orphan containers. Volumes are removed if Context.RemoveVolumes is set.
+func (p *Project) Teardown(options DownOptions) error {
+	if err := p.Down(); err != nil {
+		return err
+	}
+
+	if err := p.Delete(); err != nil {
+		return err
+	}
+
+	if p.context.Resources != nil {
+		if err := p.context.Resources.RemoveOneOffs(p); err != nil {
+			return err
+		}
+	}
+
+	if options.RemoveOrphans {
+		if err := p.RemoveOrphans(); err != nil {
+			return err
+		}
+	}
+
+	if options.RemoveImages == IMAGE_TYPE_NONE {
+		return nil
+	}
+
+	return p.forEach([]string{}, SELECT_NAMED, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
+		wrapper.Do(nil, NO_EVENT, NO_EVENT, func(service Service) error {
+			return service.RemoveImage(options.RemoveImages)
+		})
+	}), nil)
+}
+
+// Orphans returns the names of the containers of the services that are no
+// longer defined in the project.
+func (p *Project) Orphans() ([]string, error) {
+	if p.context.Resources == nil {
+		return []string{}, nil
+	}
+	return p.context.Resources.Orphans(p)
+}
+
+// RemoveOrphans removes the containers of the services that are no longer
+// defined in the project.
+func (p *Project) RemoveOrphans() error {
+	if p.context.Resources == nil {
+		return nil
+	}
+	return p.context.Resources.RemoveOrphans(p)
+}
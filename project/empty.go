@@ -39,10 +39,26 @@ func (e *EmptyService) Pull() error {
 	return nil
 }
 
+func (e *EmptyService) Push() error {
+	return nil
+}
+
 func (e *EmptyService) Kill() error {
 	return nil
 }
 
+func (e *EmptyService) Pause() error {
+	return nil
+}
+
+func (e *EmptyService) Unpause() error {
+	return nil
+}
+
+func (e *EmptyService) RemoveImage(imageType ImageType) error {
+	return nil
+}
+
 func (e *EmptyService) Containers() ([]Container, error) {
 	return []Container{}, nil
 }
@@ -54,3 +70,19 @@ func (e *EmptyService) Scale(count int) error {
 func (e *EmptyService) Info() (InfoSet, error) {
 	return InfoSet{}, nil
 }
+
+func (e *EmptyService) WaitFor(condition DependencyCondition) error {
+	return nil
+}
+
+func (e *EmptyService) Run(options RunOptions) (int, error) {
+	return 0, ErrUnsupported
+}
+
+func (e *EmptyService) Digest() (string, error) {
+	return "", ErrUnsupported
+}
+
+func (e *EmptyService) Exec(index int, options ExecOptions) (int, error) {
+	return 0, ErrUnsupported
+}
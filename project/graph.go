@@ -0,0 +1,168 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DependencyEdge is a typed relationship between a service (From) and a
+// service it depends on (To).
+type DependencyEdge struct {
+	From, To string
+	Type     ServiceRelationshipType
+}
+
+// DependencyGraph describes how the services of a project depend on each other.
+type DependencyGraph struct {
+	// Nodes holds the sorted names of the services.
+	Nodes []string
+	Edges []DependencyEdge
+	// Levels groups the services by topological level: the services of a
+	// level only depend on services of the previous levels.
+	Levels [][]string
+}
+
+// DependencyGraph computes the dependency graph of the services of the
+// project. It returns an error if the dependencies contain a cycle.
+func (p *Project) DependencyGraph() (*DependencyGraph, error) {
+	return p.dependencyGraph(p.dependentServices)
+}
+
+// configDependencyGraph computes the dependency graph from the service
+// configurations alone, without creating the services.
+func (p *Project) configDependencyGraph() (*DependencyGraph, error) {
+	return p.dependencyGraph(func(name string) ([]ServiceRelationship, error) {
+		return configDependentServices(p, p.Configs[name]), nil
+	})
+}
+
+func (p *Project) dependencyGraph(dependentServices func(name string) ([]ServiceRelationship, error)) (*DependencyGraph, error) {
+	graph := &DependencyGraph{}
+	deps := map[string][]string{}
+
+	for name := range p.Configs {
+		graph.Nodes = append(graph.Nodes, name)
+	}
+	sort.Strings(graph.Nodes)
+
+	for _, name := range graph.Nodes {
+		rels, err := dependentServices(name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rel := range rels {
+			if _, ok := p.Configs[rel.Target]; !ok {
+				continue
+			}
+
+			graph.Edges = append(graph.Edges, DependencyEdge{
+				From: name,
+				To:   rel.Target,
+				Type: rel.Type,
+			})
+
+			if !rel.Optional {
+				deps[name] = append(deps[name], rel.Target)
+			}
+		}
+	}
+
+	levels := map[string]int{}
+	for _, name := range graph.Nodes {
+		if _, err := computeLevel(name, deps, levels, []string{}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range graph.Nodes {
+		level := levels[name]
+		for len(graph.Levels) <= level {
+			graph.Levels = append(graph.Levels, []string{})
+		}
+		graph.Levels[level] = append(graph.Levels[level], name)
+	}
+
+	return graph, nil
+}
+
+func (p *Project) dependentServices(name string) ([]ServiceRelationship, error) {
+	if p.context.ServiceFactory == nil {
+		return configDependentServices(p, p.Configs[name]), nil
+	}
+
+	service, err := p.CreateService(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return service.DependentServices(), nil
+}
+
+func computeLevel(name string, deps map[string][]string, levels map[string]int, history []string) (int, error) {
+	if level, ok := levels[name]; ok {
+		return level, nil
+	}
+
+	for i, previous := range history {
+		if previous == name {
+			cycle := append(history[i:], name)
+			return 0, fmt.Errorf("Cycle detected in path %s", strings.Join(cycle, "->"))
+		}
+	}
+
+	history = append(history, name)
+	level := 0
+
+	for _, dep := range deps[name] {
+		depLevel, err := computeLevel(dep, deps, levels, history)
+		if err != nil {
+			return 0, err
+		}
+		if depLevel+1 > level {
+			level = depLevel + 1
+		}
+	}
+
+	levels[name] = level
+	return level, nil
+}
+
+// DOT renders the graph in the Graphviz DOT language.
+func (g *DependencyGraph) DOT(name string) string {
+	buffer := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(buffer, "digraph %q {\n", name)
+	for _, level := range g.Levels {
+		buffer.WriteString("  { rank=same;")
+		for _, node := range level {
+			fmt.Fprintf(buffer, " %q;", node)
+		}
+		buffer.WriteString(" }\n")
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(buffer, "  %q -> %q [label=%q];\n", edge.From, edge.To, edge.Type.String())
+	}
+	buffer.WriteString("}\n")
+
+	return buffer.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart.
+func (g *DependencyGraph) Mermaid() string {
+	buffer := bytes.NewBuffer(nil)
+	ids := map[string]string{}
+
+	buffer.WriteString("graph TD\n")
+	for i, node := range g.Nodes {
+		ids[node] = fmt.Sprintf("s%d", i)
+		fmt.Fprintf(buffer, "  %s[\"%s\"]\n", ids[node], node)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(buffer, "  %s -->|%s| %s\n", ids[edge.From], edge.Type, ids[edge.To])
+	}
+
+	return buffer.String()
+}
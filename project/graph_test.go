@@ -0,0 +1,104 @@
+package project
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	p := newTestProject(&TestServiceFactory{})
+	p.AddConfig("data", &ServiceConfig{})
+	p.Configs["db"].VolumesFrom = []string{"data"}
+
+	graph, err := p.DependencyGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedLevels := [][]string{
+		{"cache", "data"},
+		{"db"},
+		{"web"},
+		{"proxy"},
+	}
+	if len(graph.Levels) != len(expectedLevels) {
+		t.Fatalf("Expected levels %v, got %v", expectedLevels, graph.Levels)
+	}
+	for i, level := range expectedLevels {
+		if strings.Join(level, ",") != strings.Join(graph.Levels[i], ",") {
+			t.Fatalf("Expected levels %v, got %v", expectedLevels, graph.Levels)
+		}
+	}
+
+	found := false
+	for _, edge := range graph.Edges {
+		if edge.From == "db" && edge.To == "data" {
+			found = edge.Type == REL_TYPE_VOLUMES_FROM
+		}
+	}
+	if !found {
+		t.Fatalf("Expected a volumesFrom edge from db to data, got %v", graph.Edges)
+	}
+
+	dot := graph.DOT("test")
+	if !strings.Contains(dot, `"web" -> "db" [label="link"];`) {
+		t.Fatalf("Unexpected DOT output:\n%s", dot)
+	}
+
+	mermaid := graph.Mermaid()
+	if !strings.Contains(mermaid, `-->|volumesFrom|`) {
+		t.Fatalf("Unexpected Mermaid output:\n%s", mermaid)
+	}
+}
+
+func TestLoadDetectsCycles(t *testing.T) {
+	p := NewProject(&Context{
+		ServiceFactory: &TestServiceFactory{},
+	})
+
+	err := p.Load([]byte(`
+a:
+  image: busybox
+  links:
+  - b
+b:
+  image: busybox
+  volumes_from:
+  - a
+`))
+	if err == nil || !strings.Contains(err.Error(), "Cycle detected") {
+		t.Fatalf("Expected a cycle to be detected, got %v", err)
+	}
+}
+
+func TestLoadDoesNotCreateServices(t *testing.T) {
+	factory := &TestServiceFactory{}
+	p := NewProject(&Context{
+		ServiceFactory: &countingServiceFactory{factory: factory},
+	})
+
+	err := p.Load([]byte(`
+a:
+  image: busybox
+  links:
+  - b
+b:
+  image: busybox
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.context.ServiceFactory.(*countingServiceFactory).count != 0 {
+		t.Fatal("Expected Load not to create services")
+	}
+}
+
+type countingServiceFactory struct {
+	factory *TestServiceFactory
+	count   int
+}
+
+func (c *countingServiceFactory) Create(project *Project, name string, serviceConfig *ServiceConfig) (Service, error) {
+	c.count++
+	return c.factory.Create(project, name, serviceConfig)
+}
@@ -30,6 +30,15 @@ func GetServiceHash(service Service) string {
 		valueField := val.Field(i)
 		keyField := val.Type().Field(i)
 
+		if keyField.Tag.Get("hash") == "-" {
+			continue
+		}
+
+		// Services without health check keep the hash they had before it
+		if healthCheck, ok := valueField.Interface().(*HealthCheck); ok && healthCheck == nil {
+			continue
+		}
+
 		serviceKeys = append(serviceKeys, keyField.Name)
 		unsortedKeyValue[keyField.Name] = valueField.Interface()
 	}
@@ -98,6 +107,8 @@ func GetServiceHash(service Service) string {
 			for _, sliceKey := range sliceKeys {
 				io.WriteString(hash, fmt.Sprintf("%s, ", sliceKey))
 			}
+		case *HealthCheck:
+			io.WriteString(hash, fmt.Sprintf("%v", *s))
 		default:
 			io.WriteString(hash, fmt.Sprintf("%v", serviceValue))
 		}
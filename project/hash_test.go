@@ -0,0 +1,51 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceHashIsStable(t *testing.T) {
+	newService := func() Service {
+		return &TestService{
+			name: "web",
+			config: &ServiceConfig{
+				Image: "nginx",
+				HealthCheck: &HealthCheck{
+					Test: NewStringorslice("curl", "-f", "http://localhost"),
+				},
+				Ready: &ReadyProbe{
+					Http: &HttpProbe{Port: 80, Path: "/health"},
+				},
+			},
+		}
+	}
+
+	assert.Equal(t, GetServiceHash(newService()), GetServiceHash(newService()))
+}
+
+func TestServiceHashIgnoresReadyProbe(t *testing.T) {
+	service := &TestService{
+		name:   "web",
+		config: &ServiceConfig{Image: "nginx"},
+	}
+	hash := GetServiceHash(service)
+
+	service.config.Ready = &ReadyProbe{Tcp: 80}
+	assert.Equal(t, hash, GetServiceHash(service))
+
+	service.config.Image = "nginx:1.9"
+	assert.NotEqual(t, hash, GetServiceHash(service))
+}
+
+// The hash of existing services must not change with new config fields, or
+// their containers would need rebuilding after an upgrade.
+func TestServiceHashOfPlainService(t *testing.T) {
+	service := &TestService{
+		name:   "web",
+		config: &ServiceConfig{Image: "nginx"},
+	}
+
+	assert.Equal(t, "6a36f08e88a66342a17f740b84f52d14bd3f6f66", GetServiceHash(service))
+}
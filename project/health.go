@@ -0,0 +1,98 @@
+package project
+
+import (
+	"fmt"
+	"time"
+)
+
+// Health statuses of a container, as reported by Docker.
+const (
+	HEALTH_STARTING  = "starting"
+	HEALTH_HEALTHY   = "healthy"
+	HEALTH_UNHEALTHY = "unhealthy"
+)
+
+const (
+	defaultHealthInterval = 30 * time.Second
+	defaultHealthTimeout  = 30 * time.Second
+	defaultHealthRetries  = 3
+)
+
+// Enabled returns whether the health check is defined and not disabled.
+func (h *HealthCheck) Enabled() bool {
+	if h == nil || h.Disable {
+		return false
+	}
+
+	test := h.Test.Slice()
+	return len(test) > 0 && test[0] != "NONE"
+}
+
+// Command returns the test of the health check in the form used by Docker:
+// either CMD followed by the command and its arguments, or CMD-SHELL followed
+// by a command line.
+func (h *HealthCheck) Command() []string {
+	test := h.Test.Slice()
+	if len(test) == 0 {
+		return nil
+	}
+
+	switch test[0] {
+	case "CMD", "CMD-SHELL", "NONE":
+		return test
+	}
+
+	if len(test) == 1 {
+		return []string{"CMD-SHELL", test[0]}
+	}
+	return append([]string{"CMD"}, test...)
+}
+
+// Exec returns the command line to execute to run the health check.
+func (h *HealthCheck) Exec() []string {
+	command := h.Command()
+	if len(command) < 2 {
+		return nil
+	}
+
+	if command[0] == "CMD-SHELL" {
+		return []string{"/bin/sh", "-c", command[1]}
+	}
+	return command[1:]
+}
+
+// IntervalDuration returns the time between two checks.
+func (h *HealthCheck) IntervalDuration() (time.Duration, error) {
+	return parseProbeDuration("healthcheck interval", h.Interval, defaultHealthInterval)
+}
+
+// TimeoutDuration returns the time after which a check is considered failed.
+func (h *HealthCheck) TimeoutDuration() (time.Duration, error) {
+	return parseProbeDuration("healthcheck timeout", h.Timeout, defaultHealthTimeout)
+}
+
+// StartPeriodDuration returns the time during which failures don't count.
+func (h *HealthCheck) StartPeriodDuration() (time.Duration, error) {
+	return parseProbeDuration("healthcheck start_period", h.StartPeriod, 0)
+}
+
+// RetriesCount returns the number of consecutive failures needed to
+// consider a container unhealthy.
+func (h *HealthCheck) RetriesCount() int {
+	if h.Retries <= 0 {
+		return defaultHealthRetries
+	}
+	return h.Retries
+}
+
+func parseProbeDuration(name, value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s %s: %v", name, value, err)
+	}
+	return duration, nil
+}
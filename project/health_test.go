@@ -0,0 +1,54 @@
+package project
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestHealthCheckYaml(t *testing.T) {
+	config := &ServiceConfig{}
+	err := yaml.Unmarshal([]byte(`
+healthcheck:
+  test: curl -f http://localhost
+  interval: 10s
+  retries: 5
+  start_period: 1m
+`), config)
+	assert.Nil(t, err)
+
+	healthCheck := config.HealthCheck
+	assert.True(t, healthCheck.Enabled())
+	assert.Equal(t, []string{"CMD-SHELL", "curl -f http://localhost"}, healthCheck.Command())
+	assert.Equal(t, []string{"/bin/sh", "-c", "curl -f http://localhost"}, healthCheck.Exec())
+
+	interval, err := healthCheck.IntervalDuration()
+	assert.Nil(t, err)
+	assert.Equal(t, 10*time.Second, interval)
+
+	timeout, err := healthCheck.TimeoutDuration()
+	assert.Nil(t, err)
+	assert.Equal(t, defaultHealthTimeout, timeout)
+
+	startPeriod, err := healthCheck.StartPeriodDuration()
+	assert.Nil(t, err)
+	assert.Equal(t, time.Minute, startPeriod)
+
+	assert.Equal(t, 5, healthCheck.RetriesCount())
+}
+
+func TestHealthCheckCommand(t *testing.T) {
+	healthCheck := &HealthCheck{Test: NewStringorslice("CMD", "pg_isready", "-U", "postgres")}
+	assert.Equal(t, []string{"CMD", "pg_isready", "-U", "postgres"}, healthCheck.Command())
+	assert.Equal(t, []string{"pg_isready", "-U", "postgres"}, healthCheck.Exec())
+
+	var nilHealthCheck *HealthCheck
+	assert.False(t, nilHealthCheck.Enabled())
+	assert.False(t, (&HealthCheck{Test: NewStringorslice("NONE")}).Enabled())
+	assert.False(t, (&HealthCheck{Test: NewStringorslice("true"), Disable: true}).Enabled())
+
+	_, err := (&HealthCheck{Interval: "often"}).IntervalDuration()
+	assert.NotNil(t, err)
+}
@@ -2,10 +2,138 @@ package project
 
 import (
 	"bytes"
+	"fmt"
 	"io"
+	"strings"
 	"text/tabwriter"
+	"time"
 )
 
+const (
+	STATE_CREATED    = "created"
+	STATE_RUNNING    = "running"
+	STATE_PAUSED     = "paused"
+	STATE_RESTARTING = "restarting"
+	STATE_EXITED     = "exited"
+)
+
+// Info describes a container of a service. It used to be a list of InfoPart,
+// which InfoFromParts converts.
+type Info struct {
+	Id      string    `json:"id"`
+	Name    string    `json:"name"`
+	Service string    `json:"service"`
+	Image   string    `json:"image"`
+	Command string    `json:"command"`
+	Created time.Time `json:"created"`
+	// State is one of the STATE_ constants.
+	State string `json:"state"`
+	// Status describes the state for humans, like "Up 5 minutes".
+	Status   string `json:"status"`
+	ExitCode int    `json:"exitCode"`
+	// Health is the health status of the container, if its service has a
+	// health check.
+	Health string        `json:"health,omitempty"`
+	Ports  []PortBinding `json:"ports"`
+}
+
+// PortBinding is a port exposed by a container, and where it is published
+// on the host if it is.
+type PortBinding struct {
+	HostIp        string `json:"hostIp,omitempty"`
+	HostPort      int    `json:"hostPort,omitempty"`
+	ContainerPort int    `json:"containerPort"`
+	Protocol      string `json:"protocol"`
+}
+
+func (p PortBinding) String() string {
+	if p.HostPort > 0 {
+		return fmt.Sprintf("%s:%d->%d/%s", p.HostIp, p.HostPort, p.ContainerPort, p.Protocol)
+	}
+	return fmt.Sprintf("%d/%s", p.ContainerPort, p.Protocol)
+}
+
+// Parts returns the parts of the info shown in the table of containers.
+func (info Info) Parts() []InfoPart {
+	status := info.Status
+	if info.Health != "" && !strings.Contains(status, "("+info.Health) {
+		status = fmt.Sprintf("%s (%s)", status, info.Health)
+	}
+
+	ports := []string{}
+	for _, port := range info.Ports {
+		ports = append(ports, port.String())
+	}
+
+	return []InfoPart{
+		{Key: "Name", Value: info.Name},
+		{Key: "Command", Value: info.Command},
+		{Key: "State", Value: status},
+		{Key: "Ports", Value: strings.Join(ports, ", ")},
+	}
+}
+
+// InfoFromParts returns the info of the parts that Service.Info used to
+// return, for implementations that still describe containers as parts. The
+// Name and Command parts are kept, and the State part as the Status.
+func InfoFromParts(parts []InfoPart) Info {
+	info := Info{}
+
+	for _, part := range parts {
+		switch part.Key {
+		case "Name":
+			info.Name = part.Value
+		case "Command":
+			info.Command = part.Value
+		case "State":
+			info.Status = part.Value
+		}
+	}
+
+	return info
+}
+
+// Filter returns the infos matching every filter, as key=value. The keys are
+// service, status, name and health. Like with docker ps, status matches the
+// State, like running.
+func (infos InfoSet) Filter(filters ...string) (InfoSet, error) {
+	result := InfoSet{}
+
+	for _, info := range infos {
+		match := true
+		for _, filter := range filters {
+			parts := strings.SplitN(filter, "=", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("Invalid filter %s, expected key=value", filter)
+			}
+
+			var value string
+			switch parts[0] {
+			case "service":
+				value = info.Service
+			case "status":
+				value = info.State
+			case "name":
+				value = info.Name
+			case "health":
+				value = info.Health
+			default:
+				return nil, fmt.Errorf("Invalid filter %s, expected service, status, name or health", parts[0])
+			}
+
+			if value != parts[1] {
+				match = false
+			}
+		}
+
+		if match {
+			result = append(result, info)
+		}
+	}
+
+	return result, nil
+}
+
 func (infos InfoSet) String() string {
 	//no error checking, none of this should fail
 	buffer := bytes.NewBuffer(make([]byte, 0, 1024))
@@ -14,19 +142,19 @@ func (infos InfoSet) String() string {
 	first := true
 	for _, info := range infos {
 		if first {
-			writeLine(tabwriter, true, info)
+			writeLine(tabwriter, true, info.Parts())
 		}
 		first = false
-		writeLine(tabwriter, false, info)
+		writeLine(tabwriter, false, info.Parts())
 	}
 
 	tabwriter.Flush()
 	return buffer.String()
 }
 
-func writeLine(writer io.Writer, key bool, info Info) {
+func writeLine(writer io.Writer, key bool, parts []InfoPart) {
 	first := true
-	for _, part := range info {
+	for _, part := range parts {
 		if !first {
 			writer.Write([]byte{'\t'})
 		}
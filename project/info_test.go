@@ -0,0 +1,70 @@
+package project
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testInfoSet() InfoSet {
+	return InfoSet{
+		{
+			Id:      "1",
+			Name:    "app_web_1",
+			Service: "web",
+			Command: "nginx",
+			State:   STATE_RUNNING,
+			Status:  "Up 5 minutes",
+			Health:  HEALTH_HEALTHY,
+			Ports: []PortBinding{
+				{HostIp: "0.0.0.0", HostPort: 8080, ContainerPort: 80, Protocol: "tcp"},
+				{ContainerPort: 443, Protocol: "tcp"},
+			},
+		},
+		{
+			Id:      "2",
+			Name:    "app_db_1",
+			Service: "db",
+			Command: "postgres",
+			State:   STATE_EXITED,
+			Status:  "Exited (1) 2 minutes ago",
+		},
+	}
+}
+
+func TestInfoSetString(t *testing.T) {
+	lines := strings.Split(testInfoSet().String(), "\n")
+
+	assert.Equal(t, "Name       Command   State                     Ports", strings.TrimSpace(lines[0]))
+	assert.Contains(t, lines[1], "Up 5 minutes (healthy)")
+	assert.Contains(t, lines[1], "0.0.0.0:8080->80/tcp, 443/tcp")
+}
+
+func TestInfoSetFilter(t *testing.T) {
+	infos, err := testInfoSet().Filter("status=running")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(infos))
+	assert.Equal(t, "1", infos[0].Id)
+
+	infos, err = testInfoSet().Filter("service=db", "status=running")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(infos))
+
+	_, err = testInfoSet().Filter("image=nginx")
+	assert.NotNil(t, err)
+
+	_, err = testInfoSet().Filter("running")
+	assert.NotNil(t, err)
+}
+
+func TestInfoFromParts(t *testing.T) {
+	info := InfoFromParts([]InfoPart{
+		{Key: "Name", Value: "app_web_1"},
+		{Key: "Command", Value: "nginx"},
+		{Key: "State", Value: "Up 5 minutes"},
+		{Key: "Ports", Value: "80/tcp"},
+	})
+
+	assert.Equal(t, Info{Name: "app_web_1", Command: "nginx", Status: "Up 5 minutes"}, info)
+}
@@ -12,16 +12,24 @@ var (
 		PROJECT_DELETE_START:  true,
 		PROJECT_DOWN_DONE:     true,
 		PROJECT_DOWN_START:    true,
+		PROJECT_PAUSE_DONE:    true,
+		PROJECT_PAUSE_START:   true,
 		PROJECT_RESTART_DONE:  true,
 		PROJECT_RESTART_START: true,
 		PROJECT_UP_DONE:       true,
+		PROJECT_UNPAUSE_DONE:  true,
+		PROJECT_UNPAUSE_START: true,
 		PROJECT_UP_START:      true,
 		SERVICE_DELETE_START:  true,
 		SERVICE_DELETE:        true,
 		SERVICE_DOWN_START:    true,
 		SERVICE_DOWN:          true,
+		SERVICE_PAUSE_START:   true,
+		SERVICE_PAUSE:         true,
 		SERVICE_RESTART_START: true,
 		SERVICE_RESTART:       true,
+		SERVICE_UNPAUSE_START: true,
+		SERVICE_UNPAUSE:       true,
 		SERVICE_UP_START:      true,
 		SERVICE_UP:            true,
 	}
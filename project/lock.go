@@ -0,0 +1,231 @@
+package project
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ImageLock pins the image of each service to the digest it resolved to
+// when the project was locked.
+type ImageLock struct {
+	Services map[string]LockedImage `yaml:"services"`
+}
+
+// LockedImage is the image reference of a service and its digest.
+type LockedImage struct {
+	Image  string `yaml:"image"`
+	Digest string `yaml:"digest"`
+}
+
+// Pinned returns the reference of the image by digest, like
+// nginx@sha256:2a8d...
+func (l LockedImage) Pinned() string {
+	repository := l.Image
+	if index := strings.Index(repository, "@"); index >= 0 {
+		repository = repository[:index]
+	} else if index := strings.LastIndex(repository, ":"); index >= 0 && !strings.Contains(repository[index:], "/") {
+		repository = repository[:index]
+	}
+	return repository + "@" + l.Digest
+}
+
+// ReadImageLock reads the lock file at the specified path.
+func ReadImageLock(path string) (*ImageLock, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lock := &ImageLock{}
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("Failed to parse %s: %v", path, err)
+	}
+	if lock.Services == nil {
+		lock.Services = map[string]LockedImage{}
+	}
+	return lock, nil
+}
+
+// Write writes the lock file at the specified path.
+func (l *ImageLock) Write(path string) error {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// LockFilePath returns the path of the lock file of the project: the
+// LockFile of the context if set, else the compose file with the .lock
+// extension, like docker-compose.lock next to docker-compose.yml.
+func (c *Context) LockFilePath() string {
+	if c.LockFile != "" {
+		return c.LockFile
+	}
+
+	composeFile := c.ComposeFile
+	if composeFile == "" || composeFile == "-" {
+		composeFile = "docker-compose.yml"
+	}
+	return strings.TrimSuffix(composeFile, filepath.Ext(composeFile)) + ".lock"
+}
+
+// Lock resolves the image of the specified services, or of all the
+// services, to a digest and writes them to the lock file of the project.
+// The entries of the other services are kept. The services that are built
+// are skipped.
+func (p *Project) Lock(services ...string) (*ImageLock, error) {
+	names, err := p.lockedServices(services)
+	if err != nil {
+		return nil, err
+	}
+
+	path := p.context.LockFilePath()
+	lock, err := ReadImageLock(path)
+	if os.IsNotExist(err) || len(services) == 0 {
+		lock, err = &ImageLock{Services: map[string]LockedImage{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		digest, err := p.digest(name)
+		if err != nil {
+			return nil, err
+		}
+		lock.Services[name] = LockedImage{
+			Image:  p.Configs[name].Image,
+			Digest: digest,
+		}
+	}
+
+	return lock, lock.Write(path)
+}
+
+// CheckLock returns an error if one of the specified services, or of all the
+// services, or one of their dependencies isn't locked with its image, or if
+// its image no longer resolves to the digest of the lock file because its
+// tag moved. The digests are resolved through the registries.
+func (p *Project) CheckLock(services ...string) error {
+	lock, names, err := p.readLock(services)
+	if err != nil {
+		return err
+	}
+
+	return p.checkDigests(lock, names)
+}
+
+// checkDigests returns an error if the image of one of the services no
+// longer resolves to the digest of the lock file.
+func (p *Project) checkDigests(lock *ImageLock, names []string) error {
+	for _, name := range names {
+		digest, err := p.digest(name)
+		if err != nil {
+			return err
+		}
+		if locked := lock.Services[name]; digest != locked.Digest {
+			return fmt.Errorf("Image %s of service %s resolves to %s but %s pins %s", locked.Image, name, digest, p.context.LockFilePath(), locked.Digest)
+		}
+	}
+
+	return nil
+}
+
+// ImageOf returns the image the containers of the service are created from:
+// the image pinned by the lock file if the lock is enforced, else the image
+// of the service.
+func (c *Context) ImageOf(name string, config *ServiceConfig) string {
+	if c.imageLock != nil {
+		if locked, ok := c.imageLock.Services[name]; ok && locked.Image == config.Image {
+			return locked.Pinned()
+		}
+	}
+	return config.Image
+}
+
+// enforceLock makes the specified services, and their dependencies, use the
+// images pinned by the lock file, failing like CheckLock if one of them isn't
+// locked with its image or if its tag moved.
+func (p *Project) enforceLock(services []string) error {
+	lock, names, err := p.readLock(services)
+	if err != nil {
+		return err
+	}
+
+	if err := p.checkDigests(lock, names); err != nil {
+		return err
+	}
+
+	p.context.imageLock = lock
+	return nil
+}
+
+// readLock reads the lock file and returns it with the names of the locked
+// services among the specified services and their dependencies. It fails if
+// one of them isn't locked with its image.
+func (p *Project) readLock(services []string) (*ImageLock, []string, error) {
+	services, err := p.expandSelection(services, SELECT_WITH_DEPENDENCIES)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names, err := p.lockedServices(services)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	path := p.context.LockFilePath()
+	lock, err := ReadImageLock(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, name := range names {
+		image := p.Configs[name].Image
+		if locked, ok := lock.Services[name]; !ok || locked.Image != image {
+			return nil, nil, fmt.Errorf("Image %s of service %s isn't locked in %s", image, name, path)
+		}
+	}
+
+	return lock, names, nil
+}
+
+// lockedServices returns the sorted names of the services matching the
+// selectors, or of all the services, that have an image and no build.
+func (p *Project) lockedServices(selectors []string) ([]string, error) {
+	services, err := p.SelectServices(selectors...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(services) == 0 {
+		for name := range p.Configs {
+			services = append(services, name)
+		}
+	}
+
+	names := []string{}
+	for _, name := range services {
+		if config := p.Configs[name]; config != nil && config.Image != "" && config.Build == "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (p *Project) digest(name string) (string, error) {
+	service, err := p.CreateService(name)
+	if err != nil {
+		return "", err
+	}
+	return service.Digest()
+}
@@ -0,0 +1,133 @@
+package project
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newLockProject(t *testing.T, factory *TestServiceFactory) (*Project, func()) {
+	dir, err := ioutil.TempDir("", "lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewProject(&Context{
+		ComposeFile:    filepath.Join(dir, "docker-compose.yml"),
+		ServiceFactory: factory,
+	})
+	p.AddConfig("web", &ServiceConfig{Image: "nginx:1.9"})
+	p.AddConfig("db", &ServiceConfig{Image: "postgres"})
+	p.AddConfig("app", &ServiceConfig{Build: "."})
+
+	return p, func() { os.RemoveAll(dir) }
+}
+
+func TestLockFilePath(t *testing.T) {
+	assert.Equal(t, "app/docker-compose.lock", (&Context{ComposeFile: "app/docker-compose.yml"}).LockFilePath())
+	assert.Equal(t, "docker-compose.lock", (&Context{ComposeFile: "-"}).LockFilePath())
+	assert.Equal(t, "images.lock", (&Context{ComposeFile: "docker-compose.yml", LockFile: "images.lock"}).LockFilePath())
+}
+
+func TestLockedImagePinned(t *testing.T) {
+	assert.Equal(t, "nginx@sha256:abc", LockedImage{Image: "nginx:1.9", Digest: "sha256:abc"}.Pinned())
+	assert.Equal(t, "localhost:5000/app@sha256:abc", LockedImage{Image: "localhost:5000/app", Digest: "sha256:abc"}.Pinned())
+	assert.Equal(t, "nginx@sha256:abc", LockedImage{Image: "nginx@sha256:def", Digest: "sha256:abc"}.Pinned())
+}
+
+func TestLock(t *testing.T) {
+	factory := &TestServiceFactory{}
+	p, cleanup := newLockProject(t, factory)
+	defer cleanup()
+
+	lock, err := p.Lock()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]LockedImage{
+		"web": {Image: "nginx:1.9", Digest: "sha256:nginx:1.9"},
+		"db":  {Image: "postgres", Digest: "sha256:postgres"},
+	}, lock.Services)
+
+	read, err := ReadImageLock(p.context.LockFilePath())
+	assert.Nil(t, err)
+	assert.Equal(t, lock, read)
+
+	assert.Nil(t, p.CheckLock())
+}
+
+func TestCheckLockMovedTag(t *testing.T) {
+	factory := &TestServiceFactory{}
+	p, cleanup := newLockProject(t, factory)
+	defer cleanup()
+
+	if _, err := p.Lock(); err != nil {
+		t.Fatal(err)
+	}
+
+	factory.digests = map[string]string{"nginx:1.9": "sha256:moved"}
+	err := p.CheckLock()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "sha256:moved")
+
+	assert.Nil(t, p.CheckLock("db"))
+
+	p.context.EnforceLock = true
+	assert.NotNil(t, p.Up("web"))
+	assert.NotNil(t, p.Pull("web"))
+	assert.Empty(t, factory.order)
+
+	factory.digests = nil
+	assert.Nil(t, p.Up("web"))
+	assert.Equal(t, "nginx@sha256:nginx:1.9", p.context.ImageOf("web", p.Configs["web"]))
+	assert.Equal(t, "postgres@sha256:postgres", p.context.ImageOf("db", p.Configs["db"]))
+}
+
+func TestLockSelectors(t *testing.T) {
+	factory := &TestServiceFactory{}
+	p, cleanup := newLockProject(t, factory)
+	defer cleanup()
+
+	lock, err := p.Lock("w*")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"web"}, keys(lock.Services))
+
+	_, err = p.Lock("missing*")
+	assert.NotNil(t, err)
+}
+
+func keys(services map[string]LockedImage) []string {
+	result := []string{}
+	for name := range services {
+		result = append(result, name)
+	}
+	return result
+}
+
+func TestCheckLockChangedImage(t *testing.T) {
+	factory := &TestServiceFactory{}
+	p, cleanup := newLockProject(t, factory)
+	defer cleanup()
+
+	if _, err := p.Lock("db"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := p.CheckLock()
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "isn't locked")
+
+	if _, err := p.Lock("web"); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, p.CheckLock())
+
+	p.Configs["web"].Image = "nginx:1.10"
+	assert.NotNil(t, p.CheckLock("web"))
+
+	p.context.EnforceLock = true
+	assert.NotNil(t, p.Up("web"))
+	assert.NotNil(t, p.Pull("web"))
+	assert.Empty(t, factory.order)
+}
@@ -0,0 +1,37 @@
+package project
+
+import (
+	"fmt"
+	"time"
+)
+
+// LogOptions defines which logs of the containers are shown.
+type LogOptions struct {
+	// Tail is the number of lines to show from the end of the logs, zero
+	// meaning every line.
+	Tail int
+	// Follow keeps streaming the logs as they are written.
+	Follow bool
+	// Timestamps prefixes each line with its timestamp.
+	Timestamps bool
+	// Since only shows the logs written after that time, if it is set.
+	Since time.Time
+}
+
+// ParseSince parses the value of the --since flag, either a timestamp like
+// 2015-10-28T15:04:05Z or a duration relative to now like 10m.
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+
+	if since, err := time.Parse(time.RFC3339, value); err == nil {
+		return since, nil
+	}
+
+	return time.Time{}, fmt.Errorf("Invalid since value %s, expected a timestamp or a duration", value)
+}
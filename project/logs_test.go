@@ -0,0 +1,27 @@
+package project
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2015, 10, 28, 15, 4, 5, 0, time.UTC)
+
+	since, err := ParseSince("", now)
+	assert.Nil(t, err)
+	assert.True(t, since.IsZero())
+
+	since, err = ParseSince("10m", now)
+	assert.Nil(t, err)
+	assert.Equal(t, now.Add(-10*time.Minute), since)
+
+	since, err = ParseSince("2015-10-28T14:00:00Z", now)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2015, 10, 28, 14, 0, 0, 0, time.UTC), since)
+
+	_, err = ParseSince("yesterday", now)
+	assert.NotNil(t, err)
+}
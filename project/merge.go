@@ -23,6 +23,7 @@ var (
 	noMerge = []string{
 		"links",
 		"volumes_from",
+		"depends_on",
 	}
 )
 
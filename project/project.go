@@ -118,11 +118,15 @@ func (p *Project) Load(bytes []byte) error {
 		}
 	}
 
+	if _, err := p.configDependencyGraph(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func (p *Project) loadWrappers(wrappers map[string]*serviceWrapper) error {
-	for _, name := range p.reload {
+func (p *Project) loadWrappers(wrappers map[string]*serviceWrapper, names []string) error {
+	for _, name := range names {
 		wrapper, err := newServiceWrapper(name, p)
 		if err != nil {
 			return err
@@ -136,7 +140,7 @@ func (p *Project) loadWrappers(wrappers map[string]*serviceWrapper) error {
 }
 
 func (p *Project) Build(services ...string) error {
-	return p.perform(PROJECT_BUILD_START, PROJECT_BUILD_DONE, services, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
+	return p.perform(PROJECT_BUILD_START, PROJECT_BUILD_DONE, services, SELECT_NAMED, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
 		wrapper.Do(wrappers, SERVICE_BUILD_START, SERVICE_BUILD, func(service Service) error {
 			return service.Build()
 		})
@@ -144,7 +148,7 @@ func (p *Project) Build(services ...string) error {
 }
 
 func (p *Project) Create(services ...string) error {
-	return p.perform(PROJECT_CREATE_START, PROJECT_CREATE_DONE, services, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
+	return p.perform(PROJECT_CREATE_START, PROJECT_CREATE_DONE, services, SELECT_WITH_DEPENDENCIES, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
 		wrapper.Do(wrappers, SERVICE_CREATE_START, SERVICE_CREATE, func(service Service) error {
 			return service.Create()
 		})
@@ -152,15 +156,15 @@ func (p *Project) Create(services ...string) error {
 }
 
 func (p *Project) Down(services ...string) error {
-	return p.perform(PROJECT_DOWN_START, PROJECT_DOWN_DONE, services, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
-		wrapper.Do(nil, SERVICE_DOWN_START, SERVICE_DOWN, func(service Service) error {
+	return p.perform(PROJECT_DOWN_START, PROJECT_DOWN_DONE, services, SELECT_NAMED, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
+		wrapper.DoReverse(wrappers, SERVICE_DOWN_START, SERVICE_DOWN, func(service Service) error {
 			return service.Down()
 		})
 	}), nil)
 }
 
 func (p *Project) Restart(services ...string) error {
-	return p.perform(PROJECT_RESTART_START, PROJECT_RESTART_DONE, services, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
+	return p.perform(PROJECT_RESTART_START, PROJECT_RESTART_DONE, services, SELECT_NAMED, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
 		wrapper.Do(wrappers, SERVICE_RESTART_START, SERVICE_RESTART, func(service Service) error {
 			return service.Restart()
 		})
@@ -168,16 +172,22 @@ func (p *Project) Restart(services ...string) error {
 }
 
 func (p *Project) Start(services ...string) error {
-	return p.perform(PROJECT_START_START, PROJECT_START_DONE, services, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
-		wrapper.Do(wrappers, SERVICE_START_START, SERVICE_START, func(service Service) error {
+	return p.perform(PROJECT_START_START, PROJECT_START_DONE, services, SELECT_NAMED, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
+		wrapper.DoStart(wrappers, SERVICE_START_START, SERVICE_START, func(service Service) error {
 			return service.Start()
 		})
 	}), nil)
 }
 
 func (p *Project) Up(services ...string) error {
-	return p.perform(PROJECT_UP_START, PROJECT_UP_DONE, services, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
-		wrapper.Do(wrappers, SERVICE_UP_START, SERVICE_UP, func(service Service) error {
+	if p.context.EnforceLock {
+		if err := p.enforceLock(services); err != nil {
+			return err
+		}
+	}
+
+	return p.perform(PROJECT_UP_START, PROJECT_UP_DONE, services, SELECT_WITH_DEPENDENCIES, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
+		wrapper.DoStart(wrappers, SERVICE_UP_START, SERVICE_UP, func(service Service) error {
 			return service.Up()
 		})
 	}), func(service Service) error {
@@ -186,51 +196,84 @@ func (p *Project) Up(services ...string) error {
 }
 
 func (p *Project) Log(services ...string) error {
-	return p.forEach(services, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
-		wrapper.Do(nil, NO_EVENT, NO_EVENT, func(service Service) error {
+	return p.forEach(services, SELECT_NAMED, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
+		wrapper.DoUnlimited(nil, NO_EVENT, NO_EVENT, func(service Service) error {
 			return service.Log()
 		})
 	}), nil)
 }
 
-func (p *Project) Pull(services ...string) error {
-	return p.forEach(services, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
-		wrapper.Do(nil, SERVICE_PULL_START, SERVICE_PULL, func(service Service) error {
-			return service.Pull()
-		})
-	}), nil)
-}
-
 func (p *Project) Delete(services ...string) error {
-	return p.perform(PROJECT_DELETE_START, PROJECT_DELETE_DONE, services, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
-		wrapper.Do(nil, SERVICE_DELETE_START, SERVICE_DELETE, func(service Service) error {
+	return p.perform(PROJECT_DELETE_START, PROJECT_DELETE_DONE, services, SELECT_NAMED, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
+		wrapper.DoReverse(wrappers, SERVICE_DELETE_START, SERVICE_DELETE, func(service Service) error {
 			return service.Delete()
 		})
 	}), nil)
 }
 
 func (p *Project) Kill(services ...string) error {
-	return p.perform(PROJECT_KILL_START, PROJECT_KILL_DONE, services, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
-		wrapper.Do(nil, SERVICE_KILL_START, SERVICE_KILL, func(service Service) error {
+	return p.perform(PROJECT_KILL_START, PROJECT_KILL_DONE, services, SELECT_NAMED, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
+		wrapper.DoReverse(wrappers, SERVICE_KILL_START, SERVICE_KILL, func(service Service) error {
 			return service.Kill()
 		})
 	}), nil)
 }
 
-func (p *Project) perform(start, done Event, services []string, action wrapperAction, cycleAction serviceAction) error {
+func (p *Project) Pause(services ...string) error {
+	return p.perform(PROJECT_PAUSE_START, PROJECT_PAUSE_DONE, services, SELECT_NAMED, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
+		wrapper.DoReverse(wrappers, SERVICE_PAUSE_START, SERVICE_PAUSE, func(service Service) error {
+			return service.Pause()
+		})
+	}), nil)
+}
+
+func (p *Project) Unpause(services ...string) error {
+	return p.perform(PROJECT_UNPAUSE_START, PROJECT_UNPAUSE_DONE, services, SELECT_NAMED, wrapperAction(func(wrapper *serviceWrapper, wrappers map[string]*serviceWrapper) {
+		wrapper.Do(wrappers, SERVICE_UNPAUSE_START, SERVICE_UNPAUSE, func(service Service) error {
+			return service.Unpause()
+		})
+	}), nil)
+}
+
+func (p *Project) perform(start, done Event, services []string, selection SelectionMode, action wrapperAction, cycleAction serviceAction) error {
 	p.Notify(start, "", nil)
 
-	err := p.forEach(services, action, cycleAction)
+	err := p.forEach(services, selection, action, cycleAction)
 
 	p.Notify(done, "", nil)
 	return err
 }
 
+// acquireSlot blocks until the parallelism limit of the project allows one more
+// service action to run, and returns the function that releases the slot. The
+// limit is read from the context on first use.
+func (p *Project) acquireSlot() func() {
+	p.slotsOnce.Do(func() {
+		if p.context.Parallelism > 0 {
+			p.slots = make(chan struct{}, p.context.Parallelism)
+		}
+	})
+
+	if p.slots == nil {
+		return func() {}
+	}
+
+	p.slots <- struct{}{}
+	return func() {
+		<-p.slots
+	}
+}
+
 func isSelected(wrapper *serviceWrapper, selected map[string]bool) bool {
 	return len(selected) == 0 || selected[wrapper.name]
 }
 
-func (p *Project) forEach(services []string, action wrapperAction, cycleAction serviceAction) error {
+func (p *Project) forEach(services []string, selection SelectionMode, action wrapperAction, cycleAction serviceAction) error {
+	services, err := p.expandSelection(services, selection)
+	if err != nil {
+		return err
+	}
+
 	selected := make(map[string]bool)
 	wrappers := make(map[string]*serviceWrapper)
 
@@ -238,7 +281,7 @@ func (p *Project) forEach(services []string, action wrapperAction, cycleAction s
 		selected[s] = true
 	}
 
-	return p.traverse(selected, wrappers, action, cycleAction)
+	return p.traverse(true, selected, wrappers, action, cycleAction)
 }
 
 func (p *Project) startService(wrappers map[string]*serviceWrapper, history []string, selected, launched map[string]bool, wrapper *serviceWrapper, action wrapperAction, cycleAction serviceAction) error {
@@ -292,16 +335,29 @@ func (p *Project) startService(wrappers map[string]*serviceWrapper, history []st
 	return nil
 }
 
-func (p *Project) traverse(selected map[string]bool, wrappers map[string]*serviceWrapper, action wrapperAction, cycleAction serviceAction) error {
+// traverse runs the action on every wrapper. On the first pass, a wrapper is
+// loaded for every service of the project; on a restart, only the services
+// that were reloaded get a new wrapper.
+func (p *Project) traverse(start bool, selected map[string]bool, wrappers map[string]*serviceWrapper, action wrapperAction, cycleAction serviceAction) error {
 	restart := false
+	names := []string{}
 
-	for _, wrapper := range wrappers {
-		if err := wrapper.Reset(); err != nil {
-			return err
+	if start {
+		for name := range p.Configs {
+			names = append(names, name)
 		}
+	} else {
+		for _, wrapper := range wrappers {
+			if err := wrapper.Reset(); err != nil {
+				return err
+			}
+		}
+		names = p.reload
 	}
 
-	p.loadWrappers(wrappers)
+	if err := p.loadWrappers(wrappers, names); err != nil {
+		return err
+	}
 
 	launched := map[string]bool{}
 
@@ -309,7 +365,7 @@ func (p *Project) traverse(selected map[string]bool, wrappers map[string]*servic
 		p.startService(wrappers, []string{}, selected, launched, wrapper, action, cycleAction)
 	}
 
-	var firstError error
+	failures := &utils.MultiError{}
 
 	for _, wrapper := range wrappers {
 		if !isSelected(wrapper, selected) {
@@ -319,9 +375,7 @@ func (p *Project) traverse(selected map[string]bool, wrappers map[string]*servic
 			restart = true
 		} else if err != nil {
 			log.Errorf("Failed to start: %s : %v", wrapper.name, err)
-			if firstError == nil {
-				firstError = err
-			}
+			failures.Append(utils.ForService(wrapper.name, err))
 		}
 	}
 
@@ -331,9 +385,9 @@ func (p *Project) traverse(selected map[string]bool, wrappers map[string]*servic
 				log.Errorf("Failed calling callback: %v", err)
 			}
 		}
-		return p.traverse(selected, wrappers, action, cycleAction)
+		return p.traverse(false, selected, wrappers, action, cycleAction)
 	} else {
-		return firstError
+		return failures.ErrorOrNil()
 	}
 }
 
@@ -2,7 +2,11 @@ package project
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestEventEquality(t *testing.T) {
@@ -19,3 +23,503 @@ func TestEventEquality(t *testing.T) {
 		t.Fatal("Events match")
 	}
 }
+
+type TestServiceFactory struct {
+	lock    sync.Mutex
+	order   []string
+	digests map[string]string
+	// delay makes the recorded actions last, to observe how many run at
+	// the same time in maxActive.
+	delay     time.Duration
+	active    int
+	maxActive int
+}
+
+type TestService struct {
+	factory *TestServiceFactory
+	name    string
+	config  *ServiceConfig
+	project *Project
+	EmptyService
+}
+
+func (t *TestService) Name() string {
+	return t.name
+}
+
+func (t *TestService) Config() *ServiceConfig {
+	return t.config
+}
+
+func (t *TestService) DependentServices() []ServiceRelationship {
+	return DefaultDependentServices(t.project, t)
+}
+
+func (t *TestService) record() error {
+	t.factory.lock.Lock()
+	t.factory.order = append(t.factory.order, t.name)
+	t.factory.active++
+	if t.factory.active > t.factory.maxActive {
+		t.factory.maxActive = t.factory.active
+	}
+	t.factory.lock.Unlock()
+
+	time.Sleep(t.factory.delay)
+
+	t.factory.lock.Lock()
+	t.factory.active--
+	t.factory.lock.Unlock()
+	return nil
+}
+
+func (t *TestService) Create() error {
+	return t.record()
+}
+
+func (t *TestService) Up() error {
+	return t.record()
+}
+
+func (t *TestService) WaitFor(condition DependencyCondition) error {
+	t.factory.lock.Lock()
+	defer t.factory.lock.Unlock()
+	t.factory.order = append(t.factory.order, t.name+":"+string(condition))
+	return nil
+}
+
+func (t *TestService) Down() error {
+	return t.record()
+}
+
+func (t *TestService) Kill() error {
+	return t.record()
+}
+
+func (t *TestService) RemoveImage(imageType ImageType) error {
+	t.factory.lock.Lock()
+	defer t.factory.lock.Unlock()
+	t.factory.order = append(t.factory.order, t.name+":"+string(imageType))
+	return nil
+}
+
+func (t *TestService) Pull() error {
+	t.record()
+	if t.config.Image == "broken" {
+		return fmt.Errorf("Failed to pull %s", t.config.Image)
+	}
+	return nil
+}
+
+func (t *TestService) Push() error {
+	t.record()
+	if t.config.Image == "broken" {
+		return fmt.Errorf("Failed to push %s", t.config.Image)
+	}
+	return nil
+}
+
+func (t *TestService) Digest() (string, error) {
+	if digest, ok := t.factory.digests[t.config.Image]; ok {
+		return digest, nil
+	}
+	return "sha256:" + t.config.Image, nil
+}
+
+func (t *TestService) Pause() error {
+	return t.record()
+}
+
+func (t *TestService) Unpause() error {
+	return t.record()
+}
+
+func (t *TestService) Delete() error {
+	return t.record()
+}
+
+func (t *TestService) Run(options RunOptions) (int, error) {
+	t.factory.lock.Lock()
+	defer t.factory.lock.Unlock()
+	t.factory.order = append(t.factory.order, t.name+":run")
+	return 3, nil
+}
+
+func (t *TestService) Containers() ([]Container, error) {
+	return []Container{&TestContainer{name: t.name + "_1", exitCode: len(t.name)}}, nil
+}
+
+type TestContainer struct {
+	name     string
+	exitCode int
+}
+
+func (t *TestContainer) Id() (string, error) {
+	return t.name, nil
+}
+
+func (t *TestContainer) Name() string {
+	return t.name
+}
+
+func (t *TestContainer) Port(port string) (string, error) {
+	return "", nil
+}
+
+func (t *TestContainer) Wait() (int, error) {
+	return t.exitCode, nil
+}
+
+func (t *TestContainer) Exec(options ExecOptions) (int, error) {
+	return 0, ErrUnsupported
+}
+
+func (t *TestContainer) Inspect() (*ContainerDetails, error) {
+	return &ContainerDetails{Name: t.name, ExitCode: t.exitCode}, nil
+}
+
+func (t *TestServiceFactory) Create(project *Project, name string, serviceConfig *ServiceConfig) (Service, error) {
+	return &TestService{
+		factory: t,
+		name:    name,
+		config:  serviceConfig,
+		project: project,
+	}, nil
+}
+
+func newTestProject(factory *TestServiceFactory) *Project {
+	p := NewProject(&Context{
+		ServiceFactory: factory,
+	})
+
+	p.AddConfig("db", &ServiceConfig{})
+	p.AddConfig("cache", &ServiceConfig{})
+	p.AddConfig("web", &ServiceConfig{
+		Links: NewMaporColonSlice([]string{"db", "cache"}),
+	})
+	p.AddConfig("proxy", &ServiceConfig{
+		Links: NewMaporColonSlice([]string{"web"}),
+	})
+
+	return p
+}
+
+func indexOf(order []string, name string) int {
+	for i, value := range order {
+		if value == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestCreateInDependencyOrder(t *testing.T) {
+	factory := &TestServiceFactory{}
+	p := newTestProject(factory)
+
+	if err := p.Create(); err != nil {
+		t.Fatal(err)
+	}
+
+	order := factory.order
+	if indexOf(order, "db") > indexOf(order, "web") ||
+		indexOf(order, "cache") > indexOf(order, "web") ||
+		indexOf(order, "web") > indexOf(order, "proxy") {
+		t.Fatalf("Services were not created in dependency order: %v", order)
+	}
+}
+
+func TestDownAfterUp(t *testing.T) {
+	factory := &TestServiceFactory{}
+	p := newTestProject(factory)
+
+	if err := p.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	factory.order = nil
+	if err := p.Down(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(factory.order) != 4 {
+		t.Fatalf("Expected the 4 services to be stopped after up, got %v", factory.order)
+	}
+}
+
+func TestParallelismLimit(t *testing.T) {
+	factory := &TestServiceFactory{delay: 10 * time.Millisecond}
+	p := newTestProject(factory)
+	p.context.Parallelism = 1
+
+	for i := 0; i < 2; i++ {
+		if err := p.Create(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(factory.order) != 8 || factory.maxActive != 1 {
+		t.Fatalf("Expected the services to be created one at a time, got %d at once for %v", factory.maxActive, factory.order)
+	}
+}
+
+func TestTeardownInReverseDependencyOrder(t *testing.T) {
+	for _, action := range []func(*Project) error{
+		func(p *Project) error { return p.Down() },
+		func(p *Project) error { return p.Kill() },
+		func(p *Project) error { return p.Delete() },
+		func(p *Project) error { return p.Pause() },
+	} {
+		factory := &TestServiceFactory{}
+		p := newTestProject(factory)
+
+		if err := action(p); err != nil {
+			t.Fatal(err)
+		}
+
+		order := factory.order
+		if len(order) != 4 {
+			t.Fatalf("Expected 4 services to be acted upon, got %v", order)
+		}
+		if indexOf(order, "proxy") > indexOf(order, "web") ||
+			indexOf(order, "web") > indexOf(order, "db") ||
+			indexOf(order, "web") > indexOf(order, "cache") {
+			t.Fatalf("Services were not torn down in reverse dependency order: %v", order)
+		}
+	}
+}
+
+func TestUnpauseInDependencyOrder(t *testing.T) {
+	factory := &TestServiceFactory{}
+	p := newTestProject(factory)
+
+	if err := p.Unpause("web", "proxy"); err != nil {
+		t.Fatal(err)
+	}
+
+	order := factory.order
+	if len(order) != 2 || order[0] != "web" || order[1] != "proxy" {
+		t.Fatalf("Expected web then proxy to be unpaused, got %v", order)
+	}
+}
+
+func TestUpWaitsForDependsOnConditions(t *testing.T) {
+	factory := &TestServiceFactory{}
+	p := newTestProject(factory)
+	p.AddConfig("migrate", &ServiceConfig{})
+	p.Configs["web"].DependsOn = NewDependsOn(map[string]DependencyCondition{
+		"db":      CONDITION_HEALTHY,
+		"migrate": CONDITION_COMPLETED_SUCCESSFULLY,
+	})
+
+	if err := p.Up("web"); err != nil {
+		t.Fatal(err)
+	}
+
+	order := factory.order
+	if indexOf(order, "db:healthy") == -1 || indexOf(order, "db:healthy") > indexOf(order, "web") ||
+		indexOf(order, "migrate:completed_successfully") == -1 || indexOf(order, "migrate:completed_successfully") > indexOf(order, "web") {
+		t.Fatalf("Expected conditions to be met before starting web: %v", order)
+	}
+
+	factory = &TestServiceFactory{}
+	p = newTestProject(factory)
+	p.Configs["web"].DependsOn = NewDependsOn(map[string]DependencyCondition{
+		"db": CONDITION_HEALTHY,
+	})
+	p.context.Selection = SELECT_NAMED
+
+	if err := p.Up("web"); err != nil {
+		t.Fatal(err)
+	}
+
+	if indexOf(factory.order, "db:healthy") != -1 {
+		t.Fatalf("Expected no condition to be checked for services that were not started: %v", factory.order)
+	}
+}
+
+func TestWaitForExit(t *testing.T) {
+	p := newTestProject(&TestServiceFactory{})
+
+	exits, err := p.WaitForExit("web", "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codes := map[string]int{}
+	for exit := range exits {
+		if exit.Err != nil {
+			t.Fatal(exit.Err)
+		}
+		codes[exit.Container] = exit.ExitCode
+	}
+
+	if len(codes) != 2 || codes["web_1"] != 3 || codes["db_1"] != 2 {
+		t.Fatalf("Unexpected exit codes: %v", codes)
+	}
+}
+
+func TestRunStartsDependencies(t *testing.T) {
+	factory := &TestServiceFactory{}
+	p := newTestProject(factory)
+
+	exitCode, err := p.Run("web", RunOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exitCode != 3 {
+		t.Fatalf("Expected exit code 3, got %d", exitCode)
+	}
+
+	order := factory.order
+	if indexOf(order, "db") < 0 || indexOf(order, "cache") < 0 ||
+		indexOf(order, "db") > indexOf(order, "web:run") ||
+		indexOf(order, "cache") > indexOf(order, "web:run") {
+		t.Fatalf("Dependencies were not started before run: %v", order)
+	}
+	if indexOf(order, "web") >= 0 || indexOf(order, "proxy") >= 0 {
+		t.Fatalf("Only dependencies should be started: %v", order)
+	}
+
+	factory = &TestServiceFactory{}
+	p = newTestProject(factory)
+
+	if _, err := p.Run("web", RunOptions{NoDeps: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(factory.order) != 1 || factory.order[0] != "web:run" {
+		t.Fatalf("Expected only web to run, got %v", factory.order)
+	}
+}
+
+type TestResources struct {
+	factory *TestServiceFactory
+}
+
+func (t *TestResources) Orphans(p *Project) ([]string, error) {
+	return []string{"project_old_1"}, nil
+}
+
+func (t *TestResources) RemoveOneOffs(p *Project) error {
+	t.factory.order = append(t.factory.order, "oneoffs")
+	return nil
+}
+
+func (t *TestResources) RemoveOrphans(p *Project) error {
+	t.factory.order = append(t.factory.order, "orphans")
+	return nil
+}
+
+func newPullProject(factory *TestServiceFactory) *Project {
+	p := NewProject(&Context{
+		ServiceFactory: factory,
+	})
+
+	p.AddConfig("web", &ServiceConfig{Image: "nginx"})
+	p.AddConfig("proxy", &ServiceConfig{Image: "nginx:latest"})
+	p.AddConfig("db", &ServiceConfig{Image: "broken"})
+	p.AddConfig("app", &ServiceConfig{Build: "."})
+
+	return p
+}
+
+func TestPullOncePerImage(t *testing.T) {
+	factory := &TestServiceFactory{}
+	p := newPullProject(factory)
+
+	events := make(chan ProjectEvent, 100)
+	p.AddListener(events)
+
+	if err := p.Pull("web", "proxy", "app"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(factory.order) != 1 || factory.order[0] != "proxy" {
+		t.Fatalf("Expected nginx to be pulled once through proxy, got %v", factory.order)
+	}
+
+	close(events)
+	for event := range events {
+		if event.Event == SERVICE_PULL && event.Data[PULL_IMAGE] != "nginx:latest" {
+			t.Fatalf("Expected the pull of %s to be for nginx:latest, got %s", event.ServiceName, event.Data[PULL_IMAGE])
+		}
+	}
+}
+
+func TestPullFailures(t *testing.T) {
+	factory := &TestServiceFactory{}
+	p := newPullProject(factory)
+
+	err := p.Pull()
+	if err == nil || !strings.Contains(err.Error(), "db") {
+		t.Fatalf("Expected the pull of db to fail, got %v", err)
+	}
+
+	sort.Strings(factory.order)
+	if fmt.Sprint(factory.order) != "[db proxy]" {
+		t.Fatalf("Expected every image to be pulled, got %v", factory.order)
+	}
+
+	p.context.IgnorePullFailures = true
+	if err := p.Pull(); err != nil {
+		t.Fatalf("Expected pull failures to be ignored, got %v", err)
+	}
+}
+
+func TestPush(t *testing.T) {
+	factory := &TestServiceFactory{}
+	p := NewProject(&Context{
+		ServiceFactory: factory,
+	})
+
+	p.AddConfig("web", &ServiceConfig{Build: ".", Image: "myorg/web"})
+	p.AddConfig("worker", &ServiceConfig{Build: ".", Image: "broken"})
+	p.AddConfig("app", &ServiceConfig{Build: "."})
+	p.AddConfig("db", &ServiceConfig{Image: "postgres"})
+
+	err := p.Push()
+	if err == nil || !strings.Contains(err.Error(), "worker") {
+		t.Fatalf("Expected the push of worker to fail, got %v", err)
+	}
+
+	sort.Strings(factory.order)
+	if fmt.Sprint(factory.order) != "[web worker]" {
+		t.Fatalf("Expected only the built services with an image to be pushed, got %v", factory.order)
+	}
+}
+
+func TestTeardown(t *testing.T) {
+	factory := &TestServiceFactory{}
+	p := newTestProject(factory)
+	p.context.Resources = &TestResources{factory}
+
+	if err := p.Teardown(DownOptions{RemoveImages: IMAGE_TYPE_LOCAL}); err != nil {
+		t.Fatal(err)
+	}
+
+	order := factory.order
+	if len(order) != 13 {
+		t.Fatalf("Expected services to be stopped, removed and their image removed, got %v", order)
+	}
+	if order[8] != "oneoffs" || indexOf(order, "orphans") >= 0 {
+		t.Fatalf("Expected one-off containers but not orphans to be removed, got %v", order)
+	}
+	if indexOf(order, "web:local") < 9 {
+		t.Fatalf("Expected images to be removed last, got %v", order)
+	}
+}
+
+func TestOrphans(t *testing.T) {
+	p := newTestProject(&TestServiceFactory{})
+
+	orphans, err := p.Orphans()
+	if err != nil || len(orphans) != 0 {
+		t.Fatalf("Expected no orphans without resources, got %v, %v", orphans, err)
+	}
+
+	p.context.Resources = &TestResources{&TestServiceFactory{}}
+
+	orphans, err = p.Orphans()
+	if err != nil || len(orphans) != 1 || orphans[0] != "project_old_1" {
+		t.Fatalf("Unexpected orphans: %v, %v", orphans, err)
+	}
+}
@@ -0,0 +1,190 @@
+package project
+
+import (
+	"fmt"
+	"sort"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/docker/docker/graph/tags"
+	"github.com/docker/docker/pkg/parsers"
+	dockerutils "github.com/docker/docker/utils"
+	"github.com/docker/libcompose/utils"
+)
+
+// PULL_PARALLELISM is how many images are pulled at the same time when the
+// parallelism of the context isn't limited.
+const PULL_PARALLELISM = 4
+
+// Keys of the data of the pull and push events.
+const (
+	PULL_IMAGE   = "image"
+	PULL_LAYER   = "layer"
+	PULL_STATUS  = "status"
+	PULL_CURRENT = "current"
+	PULL_TOTAL   = "total"
+	PULL_ERROR   = "error"
+)
+
+// PullPolicy defines when the image of a service is pulled before its
+// containers are created.
+type PullPolicy string
+
+const (
+	// PULL_POLICY_DEFAULT pulls the image if it doesn't exist locally, like
+	// missing. The services that are built are built instead of pulled.
+	PULL_POLICY_DEFAULT = PullPolicy("")
+	// PULL_POLICY_ALWAYS pulls the image every time, even if it exists.
+	PULL_POLICY_ALWAYS = PullPolicy("always")
+	// PULL_POLICY_MISSING pulls the image if it doesn't exist locally. The
+	// services that are built are built if their image can't be pulled.
+	PULL_POLICY_MISSING = PullPolicy("missing")
+	// PULL_POLICY_NEVER never pulls, the image must exist locally.
+	PULL_POLICY_NEVER = PullPolicy("never")
+	// PULL_POLICY_BUILD builds the image every time, instead of using the
+	// existing image or pulling it.
+	PULL_POLICY_BUILD = PullPolicy("build")
+)
+
+// ParsePullPolicy parses the value of the pull_policy key and of the
+// --pull flag.
+func ParsePullPolicy(value string) (PullPolicy, error) {
+	switch PullPolicy(value) {
+	case PULL_POLICY_DEFAULT, PULL_POLICY_ALWAYS, PULL_POLICY_MISSING, PULL_POLICY_NEVER, PULL_POLICY_BUILD:
+		return PullPolicy(value), nil
+	}
+	return PULL_POLICY_DEFAULT, fmt.Errorf("Invalid pull policy %s, expected always, missing, never or build", value)
+}
+
+// PullPolicyOf returns the pull policy that applies to the service: the one
+// of the context if set, else the one of the service, else the default.
+func (c *Context) PullPolicyOf(name string, config *ServiceConfig) (PullPolicy, error) {
+	policy := c.PullPolicy
+	if policy == PULL_POLICY_DEFAULT {
+		var err error
+		if policy, err = ParsePullPolicy(string(config.PullPolicy)); err != nil {
+			return PULL_POLICY_DEFAULT, fmt.Errorf("Service %s: %v", name, err)
+		}
+	}
+
+	if policy == PULL_POLICY_BUILD && config.Build == "" {
+		return PULL_POLICY_DEFAULT, fmt.Errorf("Service %s has the build pull policy but no build", name)
+	}
+
+	return policy, nil
+}
+
+// Pull pulls the images of the specified services, or of all the services.
+// Each image is pulled once, even if several services use it, and images
+// are pulled in parallel within the parallelism limit of the context, or
+// PULL_PARALLELISM. The services that are built are skipped.
+//
+// Besides the SERVICE_PULL_START and SERVICE_PULL events, sent for every
+// service of an image, the services publish SERVICE_PULL_PROGRESS events
+// with the status of each layer. Every event carries the image with its
+// tag, like nginx:latest for nginx, or pinned by the lock file if it is
+// enforced.
+func (p *Project) Pull(services ...string) error {
+	services, err := p.expandSelection(services, SELECT_NAMED)
+	if err != nil {
+		return err
+	}
+
+	if p.context.EnforceLock {
+		if err := p.enforceLock(services); err != nil {
+			return err
+		}
+	}
+
+	if len(services) == 0 {
+		for name := range p.Configs {
+			services = append(services, name)
+		}
+	}
+
+	images, byImage := p.imagesOf(services)
+
+	p.Notify(PROJECT_PULL_START, "", nil)
+
+	parallelism := p.context.Parallelism
+	if parallelism <= 0 {
+		parallelism = PULL_PARALLELISM
+	}
+
+	tasks := utils.NewInParallel(parallelism)
+	for _, image := range images {
+		image, names := image, byImage[image]
+		tasks.Add(func() error {
+			return p.pullImage(image, names)
+		})
+	}
+	err = tasks.Wait()
+
+	p.Notify(PROJECT_PULL_DONE, "", nil)
+	return err
+}
+
+// imagesOf returns the distinct images of the services, sorted, and the
+// names of the services that use each image. The images are normalized, so
+// that nginx and nginx:latest are the same image.
+func (p *Project) imagesOf(services []string) ([]string, map[string][]string) {
+	sort.Strings(services)
+
+	images := []string{}
+	byImage := map[string][]string{}
+	for _, name := range services {
+		config := p.Configs[name]
+		if config == nil || config.Image == "" || config.Build != "" {
+			continue
+		}
+		image := normalizeImage(p.context.ImageOf(name, config))
+		if _, ok := byImage[image]; !ok {
+			images = append(images, image)
+		}
+		byImage[image] = append(byImage[image], name)
+	}
+	sort.Strings(images)
+
+	return images, byImage
+}
+
+// normalizeImage returns the image with the default tag if it has neither a
+// tag nor a digest, as it is pulled.
+func normalizeImage(image string) string {
+	repository, tag := parsers.ParseRepositoryTag(image)
+	if tag != "" {
+		return image
+	}
+	return dockerutils.ImageReference(repository, tags.DEFAULTTAG)
+}
+
+// pullImage pulls the image through the first of the services that use it.
+func (p *Project) pullImage(image string, services []string) error {
+	data := map[string]string{PULL_IMAGE: image}
+	for _, name := range services {
+		p.Notify(SERVICE_PULL_START, name, data)
+	}
+
+	service, err := p.CreateService(services[0])
+	if err == nil {
+		err = service.Pull()
+	}
+
+	if err != nil {
+		for _, name := range services {
+			p.Notify(SERVICE_PULL_FAILED, name, map[string]string{
+				PULL_IMAGE: image,
+				PULL_ERROR: err.Error(),
+			})
+		}
+		if p.context.IgnorePullFailures {
+			log.Warnf("Failed to pull %s for %v: %v", image, services, err)
+			return nil
+		}
+		return utils.ForService(services[0], err)
+	}
+
+	for _, name := range services {
+		p.Notify(SERVICE_PULL, name, data)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePullPolicy(t *testing.T) {
+	for _, value := range []string{"", "always", "missing", "never", "build"} {
+		policy, err := ParsePullPolicy(value)
+		assert.Nil(t, err)
+		assert.Equal(t, PullPolicy(value), policy)
+	}
+
+	_, err := ParsePullPolicy("sometimes")
+	assert.NotNil(t, err)
+}
+
+func TestPullPolicyOf(t *testing.T) {
+	context := &Context{}
+
+	policy, err := context.PullPolicyOf("web", &ServiceConfig{})
+	assert.Nil(t, err)
+	assert.Equal(t, PULL_POLICY_DEFAULT, policy)
+
+	policy, err = context.PullPolicyOf("web", &ServiceConfig{PullPolicy: PULL_POLICY_NEVER})
+	assert.Nil(t, err)
+	assert.Equal(t, PULL_POLICY_NEVER, policy)
+
+	_, err = context.PullPolicyOf("web", &ServiceConfig{PullPolicy: "sometimes"})
+	assert.NotNil(t, err)
+
+	_, err = context.PullPolicyOf("web", &ServiceConfig{PullPolicy: PULL_POLICY_BUILD})
+	assert.NotNil(t, err)
+
+	context.PullPolicy = PULL_POLICY_ALWAYS
+	policy, err = context.PullPolicyOf("web", &ServiceConfig{PullPolicy: PULL_POLICY_NEVER})
+	assert.Nil(t, err)
+	assert.Equal(t, PULL_POLICY_ALWAYS, policy)
+}
+
+func TestNormalizeImage(t *testing.T) {
+	assert.Equal(t, "nginx:latest", normalizeImage("nginx"))
+	assert.Equal(t, "nginx:1.9", normalizeImage("nginx:1.9"))
+	assert.Equal(t, "localhost:5000/web:latest", normalizeImage("localhost:5000/web"))
+	assert.Equal(t, "nginx@sha256:abc", normalizeImage("nginx@sha256:abc"))
+}
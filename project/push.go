@@ -0,0 +1,71 @@
+package project
+
+import (
+	"sort"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/docker/libcompose/utils"
+)
+
+// Push pushes the images built for the specified services, or for all the
+// services, in parallel within the parallelism limit of the context. Only
+// the services with both a build and an image are pushed, the image being
+// the name their build is tagged with.
+//
+// The services publish SERVICE_PUSH_PROGRESS events with the status of
+// each layer, keyed like the pull events. The error of every service that
+// fails is returned.
+func (p *Project) Push(services ...string) error {
+	services, err := p.expandSelection(services, SELECT_NAMED)
+	if err != nil {
+		return err
+	}
+
+	if len(services) == 0 {
+		for name := range p.Configs {
+			services = append(services, name)
+		}
+	}
+	sort.Strings(services)
+
+	p.Notify(PROJECT_PUSH_START, "", nil)
+
+	tasks := utils.NewInParallel(p.context.Parallelism)
+	for _, name := range services {
+		config := p.Configs[name]
+		if config == nil || config.Build == "" || config.Image == "" {
+			log.Debugf("Not pushing %s, it has no build or no image", name)
+			continue
+		}
+
+		name, image := name, config.Image
+		tasks.Add(func() error {
+			return p.pushImage(name, image)
+		})
+	}
+	err = tasks.Wait()
+
+	p.Notify(PROJECT_PUSH_DONE, "", nil)
+	return err
+}
+
+func (p *Project) pushImage(name, image string) error {
+	data := map[string]string{PULL_IMAGE: image}
+	p.Notify(SERVICE_PUSH_START, name, data)
+
+	service, err := p.CreateService(name)
+	if err == nil {
+		err = service.Push()
+	}
+
+	if err != nil {
+		p.Notify(SERVICE_PUSH_FAILED, name, map[string]string{
+			PULL_IMAGE: image,
+			PULL_ERROR: err.Error(),
+		})
+		return utils.ForService(name, err)
+	}
+
+	p.Notify(SERVICE_PUSH, name, data)
+	return nil
+}
@@ -0,0 +1,68 @@
+package project
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	defaultReadyInterval = time.Second
+	defaultReadyTimeout  = 5 * time.Second
+	defaultReadyRetries  = 30
+)
+
+// Validate checks that exactly one kind of probe is defined.
+func (r *ReadyProbe) Validate() error {
+	count := 0
+	if r.Tcp > 0 {
+		count++
+	}
+	if r.Http != nil {
+		count++
+	}
+	if len(r.Exec.Slice()) > 0 {
+		count++
+	}
+
+	if count != 1 {
+		return fmt.Errorf("A ready probe must define exactly one of tcp, http or exec")
+	}
+	return nil
+}
+
+// ExecCommand returns the command line to execute for an exec probe. A
+// single string is run by the shell.
+func (r *ReadyProbe) ExecCommand() []string {
+	command := r.Exec.Slice()
+	if len(command) == 1 {
+		return []string{"/bin/sh", "-c", command[0]}
+	}
+	return command
+}
+
+// ExpectedStatus returns the HTTP status an HTTP probe expects.
+func (h *HttpProbe) ExpectedStatus() int {
+	if h.Status == 0 {
+		return 200
+	}
+	return h.Status
+}
+
+// IntervalDuration returns the time between two attempts.
+func (r *ReadyProbe) IntervalDuration() (time.Duration, error) {
+	return parseProbeDuration("ready interval", r.Interval, defaultReadyInterval)
+}
+
+// TimeoutDuration returns the time after which an attempt is considered failed.
+func (r *ReadyProbe) TimeoutDuration() (time.Duration, error) {
+	return parseProbeDuration("ready timeout", r.Timeout, defaultReadyTimeout)
+}
+
+// RetriesCount returns the number of failed attempts after which the service
+// is considered failed.
+func (r *ReadyProbe) RetriesCount() int {
+	if r.Retries <= 0 {
+		return defaultReadyRetries
+	}
+	return r.Retries
+}
@@ -0,0 +1,45 @@
+package project
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestReadyProbeYaml(t *testing.T) {
+	config := &ServiceConfig{}
+	err := yaml.Unmarshal([]byte(`
+ready:
+  http:
+    port: 8080
+    path: /healthz
+  interval: 2s
+`), config)
+	assert.Nil(t, err)
+
+	probe := config.Ready
+	assert.Nil(t, probe.Validate())
+	assert.Equal(t, 8080, probe.Http.Port)
+	assert.Equal(t, 200, probe.Http.ExpectedStatus())
+
+	interval, err := probe.IntervalDuration()
+	assert.Nil(t, err)
+	assert.Equal(t, 2*time.Second, interval)
+
+	timeout, err := probe.TimeoutDuration()
+	assert.Nil(t, err)
+	assert.Equal(t, 5*time.Second, timeout)
+	assert.Equal(t, 30, probe.RetriesCount())
+}
+
+func TestReadyProbeValidate(t *testing.T) {
+	assert.NotNil(t, (&ReadyProbe{}).Validate())
+	assert.NotNil(t, (&ReadyProbe{Tcp: 5432, Exec: NewStringorslice("true")}).Validate())
+	assert.Nil(t, (&ReadyProbe{Tcp: 5432}).Validate())
+
+	probe := &ReadyProbe{Exec: NewStringorslice("pg_isready -U postgres")}
+	assert.Nil(t, probe.Validate())
+	assert.Equal(t, []string{"/bin/sh", "-c", "pg_isready -U postgres"}, probe.ExecCommand())
+}
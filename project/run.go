@@ -0,0 +1,97 @@
+package project
+
+import (
+	"fmt"
+	"io"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// RunOptions holds the settings of a one-off container run by Project.Run.
+type RunOptions struct {
+	// Command overrides the command of the service if not empty.
+	Command []string
+	// Entrypoint overrides the entrypoint of the service if not empty.
+	Entrypoint []string
+	// Environment holds KEY=VALUE pairs added to the environment of the service.
+	Environment []string
+	// User overrides the user of the service if not empty.
+	User string
+	// Tty allocates a pseudo-TTY.
+	Tty bool
+	// Stdin, Stdout and Stderr are attached to the container if not nil.
+	// Stderr is unused with a TTY.
+	Stdin          io.Reader
+	Stdout, Stderr io.Writer
+	// Remove removes the container once it exited.
+	Remove bool
+	// NoDeps doesn't start the services the service depends on.
+	NoDeps bool
+}
+
+// Run runs a one-off container of the specified service, after starting the
+// services it depends on unless options.NoDeps is set, and returns the exit
+// code of the container.
+func (p *Project) Run(serviceName string, options RunOptions) (int, error) {
+	if _, ok := p.Configs[serviceName]; !ok {
+		return 0, fmt.Errorf("No such service: %s", serviceName)
+	}
+
+	service, err := p.CreateService(serviceName)
+	if err != nil {
+		return 0, err
+	}
+
+	if !options.NoDeps {
+		if err := p.upDependencies(service); err != nil {
+			return 0, err
+		}
+	}
+
+	return service.Run(options)
+}
+
+func (p *Project) upDependencies(service Service) error {
+	services, err := p.related([]string{service.Name()}, SELECT_WITH_DEPENDENCIES)
+	if err != nil {
+		return err
+	}
+
+	dependencies := []string{}
+	for _, name := range services {
+		if name != service.Name() {
+			dependencies = append(dependencies, name)
+		}
+	}
+
+	if len(dependencies) == 0 {
+		return nil
+	}
+
+	if err := p.Up(dependencies...); err != nil {
+		return err
+	}
+
+	for _, dep := range service.DependentServices() {
+		if _, ok := p.Configs[dep.Target]; !ok {
+			continue
+		}
+
+		target, err := p.CreateService(dep.Target)
+		if err != nil {
+			return err
+		}
+
+		condition := waitCondition(dep, target)
+		if condition == "" {
+			continue
+		}
+
+		log.Debugf("Waiting for %s to be %s", dep.Target, condition)
+		if err := target.WaitFor(condition); err != nil {
+			return fmt.Errorf("Dependency %s is not %s: %v", dep.Target, condition, err)
+		}
+	}
+
+	return nil
+}
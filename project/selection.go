@@ -0,0 +1,151 @@
+package project
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// SelectionMode defines which services, besides the ones that are named, a
+// project operation applies to.
+type SelectionMode int
+
+const (
+	// SELECT_DEFAULT uses the default mode of the operation.
+	SELECT_DEFAULT SelectionMode = iota
+	// SELECT_NAMED only selects the named services.
+	SELECT_NAMED
+	// SELECT_WITH_DEPENDENCIES selects the named services and every service
+	// they depend on.
+	SELECT_WITH_DEPENDENCIES
+	// SELECT_WITH_DEPENDENTS selects the named services and every service
+	// that depends on them.
+	SELECT_WITH_DEPENDENTS
+)
+
+// ExpandSelection returns the services an operation with the specified
+// default selection mode applies to, like Up with SELECT_WITH_DEPENDENCIES,
+// given the named services and the selection mode of the context. An empty
+// list means every service.
+func (p *Project) ExpandSelection(services []string, defaultMode SelectionMode) ([]string, error) {
+	return p.expandSelection(services, defaultMode)
+}
+
+// expandSelection returns the services an operation applies to, given the
+// named services and the selection mode of the context. An empty list means
+// every service.
+func (p *Project) expandSelection(services []string, defaultMode SelectionMode) ([]string, error) {
+	services, err := p.SelectServices(services...)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := p.context.Selection
+	if mode == SELECT_DEFAULT {
+		mode = defaultMode
+	}
+
+	if len(services) == 0 || (mode != SELECT_WITH_DEPENDENCIES && mode != SELECT_WITH_DEPENDENTS) {
+		return services, nil
+	}
+
+	return p.related(services, mode)
+}
+
+// related returns the specified services along with every service they
+// depend on (SELECT_WITH_DEPENDENCIES) or that depends on them
+// (SELECT_WITH_DEPENDENTS).
+func (p *Project) related(services []string, mode SelectionMode) ([]string, error) {
+	graph, err := p.DependencyGraph()
+	if err != nil {
+		return nil, err
+	}
+
+	next := map[string][]string{}
+	for _, edge := range graph.Edges {
+		if mode == SELECT_WITH_DEPENDENCIES {
+			next[edge.From] = append(next[edge.From], edge.To)
+		} else {
+			next[edge.To] = append(next[edge.To], edge.From)
+		}
+	}
+
+	result := []string{}
+	seen := map[string]bool{}
+	queue := append([]string{}, services...)
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+		queue = append(queue, next[name]...)
+	}
+
+	return result, nil
+}
+
+// SelectServices returns the sorted names of the services matching the
+// specified selectors. A selector is either a service name, a glob pattern
+// on service names (api-*), or a label selector on the service labels
+// (role=worker or role!=worker). It fails if a selector matches nothing.
+func (p *Project) SelectServices(selectors ...string) ([]string, error) {
+	if len(selectors) == 0 {
+		return selectors, nil
+	}
+
+	selected := map[string]bool{}
+
+	for _, selector := range selectors {
+		found := false
+		for name, config := range p.Configs {
+			match, err := matchSelector(selector, name, config)
+			if err != nil {
+				return nil, err
+			}
+			if match {
+				selected[name] = true
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("No service matches %s", selector)
+		}
+	}
+
+	result := []string{}
+	for name := range selected {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+func matchSelector(selector, name string, config *ServiceConfig) (bool, error) {
+	if parts := strings.SplitN(selector, "!=", 2); len(parts) == 2 {
+		value, ok := config.Labels.MapParts()[parts[0]]
+		return !ok || value != parts[1], nil
+	}
+
+	if parts := strings.SplitN(selector, "=", 2); len(parts) == 2 {
+		value, ok := config.Labels.MapParts()[parts[0]]
+		return ok && value == parts[1], nil
+	}
+
+	if strings.ContainsAny(selector, "*?[") {
+		match, err := path.Match(selector, name)
+		if err != nil {
+			return false, fmt.Errorf("Invalid service pattern %s: %v", selector, err)
+		}
+		return match, nil
+	}
+
+	return selector == name, nil
+}
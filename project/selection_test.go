@@ -0,0 +1,74 @@
+package project
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSelectionModes(t *testing.T) {
+	cases := []struct {
+		mode     SelectionMode
+		action   func(*Project) error
+		expected []string
+	}{
+		{SELECT_DEFAULT, func(p *Project) error { return p.Create("web") }, []string{"cache", "db", "web"}},
+		{SELECT_NAMED, func(p *Project) error { return p.Create("web") }, []string{"web"}},
+		{SELECT_WITH_DEPENDENTS, func(p *Project) error { return p.Create("web") }, []string{"proxy", "web"}},
+		{SELECT_DEFAULT, func(p *Project) error { return p.Down("db") }, []string{"db"}},
+		{SELECT_WITH_DEPENDENTS, func(p *Project) error { return p.Down("db") }, []string{"db", "proxy", "web"}},
+		{SELECT_WITH_DEPENDENCIES, func(p *Project) error { return p.Down("proxy") }, []string{"cache", "db", "proxy", "web"}},
+	}
+
+	for _, c := range cases {
+		factory := &TestServiceFactory{}
+		p := newTestProject(factory)
+		p.context.Selection = c.mode
+
+		if err := c.action(p); err != nil {
+			t.Fatal(err)
+		}
+
+		sort.Strings(factory.order)
+		if strings.Join(factory.order, ",") != strings.Join(c.expected, ",") {
+			t.Fatalf("Expected %v to be selected with mode %d, got %v", c.expected, c.mode, factory.order)
+		}
+	}
+}
+
+func TestSelectServices(t *testing.T) {
+	p := newTestProject(&TestServiceFactory{})
+	p.AddConfig("api-1", &ServiceConfig{
+		Labels: NewSliceorMap(map[string]string{"role": "worker"}),
+	})
+	p.AddConfig("api-2", &ServiceConfig{
+		Labels: NewSliceorMap(map[string]string{"role": "api"}),
+	})
+
+	cases := map[string][]string{
+		"web":         {"web"},
+		"api-*":       {"api-1", "api-2"},
+		"role=worker": {"api-1"},
+		"role!=api":   {"api-1", "cache", "db", "proxy", "web"},
+	}
+
+	for selector, expected := range cases {
+		selected, err := p.SelectServices(selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(selected, ",") != strings.Join(expected, ",") {
+			t.Fatalf("Expected %v for %s, got %v", expected, selector, selected)
+		}
+	}
+
+	for _, selector := range []string{"unknown", "role=unknown", "nothing-*"} {
+		if _, err := p.SelectServices(selector); err == nil {
+			t.Fatalf("Expected an error for %s matching nothing", selector)
+		}
+	}
+
+	if err := p.Create("role=unknown"); err == nil {
+		t.Fatal("Expected an error when an operation selects nothing")
+	}
+}
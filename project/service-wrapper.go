@@ -1,6 +1,7 @@
 package project
 
 import (
+	"fmt"
 	"sync"
 
 	log "github.com/Sirupsen/logrus"
@@ -15,6 +16,7 @@ type serviceWrapper struct {
 	project *Project
 	noWait  bool
 	ignored map[string]bool
+	skipped bool
 }
 
 func newServiceWrapper(name string, p *Project) (*serviceWrapper, error) {
@@ -55,10 +57,21 @@ func (s *serviceWrapper) Ignore() {
 	defer s.done.Done()
 
 	s.state = EXECUTED
+	s.skipped = true
 	s.project.Notify(SERVICE_UP_IGNORED, s.service.Name(), nil)
 }
 
 func (s *serviceWrapper) waitForDeps(wrappers map[string]*serviceWrapper) bool {
+	return s.waitFor(wrappers, false)
+}
+
+// waitForStartDeps is like waitForDeps but also waits for the depends_on
+// conditions of the dependencies that were started by the same operation.
+func (s *serviceWrapper) waitForStartDeps(wrappers map[string]*serviceWrapper) bool {
+	return s.waitFor(wrappers, true)
+}
+
+func (s *serviceWrapper) waitFor(wrappers map[string]*serviceWrapper, conditions bool) bool {
 	if s.noWait {
 		return true
 	}
@@ -74,6 +87,17 @@ func (s *serviceWrapper) waitForDeps(wrappers map[string]*serviceWrapper) bool {
 				s.err = ErrRestart
 				return false
 			}
+
+			condition := waitCondition(dep, wrapper.service)
+			if !conditions || wrapper.skipped || wrapper.err != nil || condition == "" {
+				continue
+			}
+
+			log.Debugf("Waiting for %s to be %s", dep.Target, condition)
+			if err := wrapper.service.WaitFor(condition); err != nil {
+				s.err = fmt.Errorf("Dependency %s is not %s: %v", dep.Target, condition, err)
+				return false
+			}
 		} else {
 			log.Errorf("Failed to find %s", dep.Target)
 		}
@@ -82,19 +106,115 @@ func (s *serviceWrapper) waitForDeps(wrappers map[string]*serviceWrapper) bool {
 	return true
 }
 
+// waitCondition returns the condition to wait for on the target of the
+// relationship before starting the dependent service, if any.
+func waitCondition(dep ServiceRelationship, target Service) DependencyCondition {
+	condition := dep.Condition
+	if condition == "" && target.Config() != nil && target.Config().HealthCheck.Enabled() {
+		// Dependencies that have a health check are waited on to be healthy,
+		// unless depends_on explicitly asks for another condition
+		condition = CONDITION_HEALTHY
+	}
+
+	if condition == CONDITION_STARTED {
+		return ""
+	}
+	return condition
+}
+
+// waitForDependents waits for every service that depends on this one, using
+// the same relationships as waitForDeps in the opposite direction.
+func (s *serviceWrapper) waitForDependents(wrappers map[string]*serviceWrapper) bool {
+	if s.noWait {
+		return true
+	}
+
+	for _, wrapper := range wrappers {
+		if wrapper == s || wrapper.ignored[s.name] {
+			continue
+		}
+
+		for _, dep := range wrapper.service.DependentServices() {
+			if dep.Target != s.name {
+				continue
+			}
+
+			if wrapper.Wait() == ErrRestart {
+				s.project.Notify(PROJECT_RELOAD, wrapper.service.Name(), nil)
+				s.err = ErrRestart
+				return false
+			}
+			break
+		}
+	}
+
+	return true
+}
+
 func (s *serviceWrapper) Do(wrappers map[string]*serviceWrapper, start, done Event, action func(service Service) error) {
+	s.do(wrappers, s.waitForDeps, start, done, action, true)
+}
+
+// DoStart is like Do but also waits for the dependencies to meet their
+// depends_on conditions, like being healthy, before running the action.
+func (s *serviceWrapper) DoStart(wrappers map[string]*serviceWrapper, start, done Event, action func(service Service) error) {
+	s.do(wrappers, s.waitForStartDeps, start, done, func(service Service) error {
+		if err := action(service); err != nil {
+			return err
+		}
+		return waitReady(service)
+	}, true)
+}
+
+// waitReady waits for the ready probe of the service, if any, to succeed.
+func waitReady(service Service) error {
+	config := service.Config()
+	if config == nil || config.Ready == nil {
+		return nil
+	}
+
+	if err := config.Ready.Validate(); err != nil {
+		return err
+	}
+
+	log.Debugf("Waiting for %s to be ready", service.Name())
+	if err := service.WaitFor(CONDITION_READY); err != nil {
+		return fmt.Errorf("Service %s is not ready: %v", service.Name(), err)
+	}
+
+	return nil
+}
+
+// DoReverse is like Do but waits for the services that depend on this one
+// instead of its dependencies, so that dependents are acted upon first.
+func (s *serviceWrapper) DoReverse(wrappers map[string]*serviceWrapper, start, done Event, action func(service Service) error) {
+	s.do(wrappers, s.waitForDependents, start, done, action, true)
+}
+
+// DoUnlimited is like Do but does not count against the project parallelism
+// limit. It is meant for actions that never complete, like following logs.
+func (s *serviceWrapper) DoUnlimited(wrappers map[string]*serviceWrapper, start, done Event, action func(service Service) error) {
+	s.do(wrappers, s.waitForDeps, start, done, action, false)
+}
+
+func (s *serviceWrapper) do(wrappers map[string]*serviceWrapper, wait func(map[string]*serviceWrapper) bool, start, done Event, action func(service Service) error, limited bool) {
 	defer s.done.Done()
 
 	if s.state == EXECUTED {
 		return
 	}
 
-	if wrappers != nil && !s.waitForDeps(wrappers) {
+	if wrappers != nil && !wait(wrappers) {
 		return
 	}
 
 	s.state = EXECUTED
 
+	if limited {
+		release := s.project.acquireSlot()
+		defer release()
+	}
+
 	s.project.Notify(start, s.service.Name(), nil)
 
 	s.err = action(s.service)
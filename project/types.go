@@ -1,6 +1,11 @@
 package project
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
 
 type Event int
 
@@ -51,6 +56,27 @@ const (
 	PROJECT_START_DONE     = Event(iota)
 	PROJECT_BUILD_START    = Event(iota)
 	PROJECT_BUILD_DONE     = Event(iota)
+
+	SERVICE_PAUSE_START   = Event(iota)
+	SERVICE_PAUSE         = Event(iota)
+	SERVICE_UNPAUSE_START = Event(iota)
+	SERVICE_UNPAUSE       = Event(iota)
+	PROJECT_PAUSE_START   = Event(iota)
+	PROJECT_PAUSE_DONE    = Event(iota)
+	PROJECT_UNPAUSE_START = Event(iota)
+	PROJECT_UNPAUSE_DONE  = Event(iota)
+
+	SERVICE_PULL_PROGRESS = Event(iota)
+	SERVICE_PULL_FAILED   = Event(iota)
+	PROJECT_PULL_START    = Event(iota)
+	PROJECT_PULL_DONE     = Event(iota)
+
+	SERVICE_PUSH_START    = Event(iota)
+	SERVICE_PUSH          = Event(iota)
+	SERVICE_PUSH_PROGRESS = Event(iota)
+	SERVICE_PUSH_FAILED   = Event(iota)
+	PROJECT_PUSH_START    = Event(iota)
+	PROJECT_PUSH_DONE     = Event(iota)
 )
 
 func (e Event) String() string {
@@ -89,6 +115,18 @@ func (e Event) String() string {
 		m = "Pulling"
 	case SERVICE_PULL:
 		m = "Pulled"
+	case SERVICE_PULL_PROGRESS:
+		m = "Pulling layer"
+	case SERVICE_PULL_FAILED:
+		m = "Failed to pull"
+	case SERVICE_PUSH_START:
+		m = "Pushing"
+	case SERVICE_PUSH:
+		m = "Pushed"
+	case SERVICE_PUSH_PROGRESS:
+		m = "Pushing layer"
+	case SERVICE_PUSH_FAILED:
+		m = "Failed to push"
 	case SERVICE_KILL_START:
 		m = "Killing"
 	case SERVICE_KILL:
@@ -101,6 +139,14 @@ func (e Event) String() string {
 		m = "Building"
 	case SERVICE_BUILD:
 		m = "Built"
+	case SERVICE_PAUSE_START:
+		m = "Pausing"
+	case SERVICE_PAUSE:
+		m = "Paused"
+	case SERVICE_UNPAUSE_START:
+		m = "Unpausing"
+	case SERVICE_UNPAUSE:
+		m = "Unpaused"
 
 	case PROJECT_DOWN_START:
 		m = "Stopping project"
@@ -138,6 +184,22 @@ func (e Event) String() string {
 		m = "Building project"
 	case PROJECT_BUILD_DONE:
 		m = "Project built"
+	case PROJECT_PAUSE_START:
+		m = "Pausing project"
+	case PROJECT_PAUSE_DONE:
+		m = "Project paused"
+	case PROJECT_UNPAUSE_START:
+		m = "Unpausing project"
+	case PROJECT_UNPAUSE_DONE:
+		m = "Project unpaused"
+	case PROJECT_PULL_START:
+		m = "Pulling project"
+	case PROJECT_PULL_DONE:
+		m = "Project pulled"
+	case PROJECT_PUSH_START:
+		m = "Pushing project"
+	case PROJECT_PUSH_DONE:
+		m = "Project pushed"
 	}
 
 	if m == "" {
@@ -152,7 +214,6 @@ type InfoPart struct {
 }
 
 type InfoSet []Info
-type Info []InfoPart
 
 type ServiceConfig struct {
 	Build         string            `yaml:"build,omitempty"`
@@ -162,6 +223,7 @@ type ServiceConfig struct {
 	CpuShares     int64             `yaml:"cpu_shares,omitempty"`
 	Command       Command           `yaml:"command"` // omitempty breaks serialization!
 	ContainerName string            `yaml:"container_name,omitempty"`
+	DependsOn     DependsOn         `yaml:"depends_on" hash:"-"` // omitempty breaks serialization!
 	Devices       []string          `yaml:"devices,omitempty"`
 	Dns           Stringorslice     `yaml:"dns"`        // omitempty breaks serialization!
 	DnsSearch     Stringorslice     `yaml:"dns_search"` // omitempty breaks serialization!
@@ -184,6 +246,7 @@ type ServiceConfig struct {
 	Ipc           string            `yaml:"ipc,omitempty"`
 	Ports         []string          `yaml:"ports,omitempty"`
 	Privileged    bool              `yaml:"privileged,omitempty"`
+	PullPolicy    PullPolicy        `yaml:"pull_policy,omitempty" hash:"-"` // doesn't change the container
 	Restart       string            `yaml:"restart,omitempty"`
 	ReadOnly      bool              `yaml:"read_only,omitempty"`
 	StdinOpen     bool              `yaml:"stdin_open,omitempty"`
@@ -198,6 +261,37 @@ type ServiceConfig struct {
 	ExternalLinks []string          `yaml:"external_links,omitempty"`
 	LogOpt        map[string]string `yaml:"log_opt,omitempty"`
 	ExtraHosts    []string          `yaml:"extra_hosts,omitempty"`
+	HealthCheck   *HealthCheck      `yaml:"healthcheck,omitempty"`
+	Ready         *ReadyProbe       `yaml:"ready,omitempty" hash:"-"` // doesn't change the container
+}
+
+// HealthCheck defines how the health of the containers of a service is checked.
+type HealthCheck struct {
+	Test        Stringorslice `yaml:"test"` // omitempty breaks serialization!
+	Interval    string        `yaml:"interval,omitempty"`
+	Timeout     string        `yaml:"timeout,omitempty"`
+	Retries     int           `yaml:"retries,omitempty"`
+	StartPeriod string        `yaml:"start_period,omitempty"`
+	Disable     bool          `yaml:"disable,omitempty"`
+}
+
+// ReadyProbe defines how to check that a service is ready once started: a
+// TCP port accepting connections, an HTTP GET returning the expected status
+// or a command exiting successfully. Exactly one of Tcp, Http or Exec is set.
+type ReadyProbe struct {
+	Tcp      int           `yaml:"tcp,omitempty"`
+	Http     *HttpProbe    `yaml:"http,omitempty"`
+	Exec     Stringorslice `yaml:"exec"` // omitempty breaks serialization!
+	Interval string        `yaml:"interval,omitempty"`
+	Timeout  string        `yaml:"timeout,omitempty"`
+	Retries  int           `yaml:"retries,omitempty"`
+}
+
+// HttpProbe is a ready probe that sends an HTTP GET request.
+type HttpProbe struct {
+	Port   int    `yaml:"port"`
+	Path   string `yaml:"path,omitempty"`
+	Status int    `yaml:"status,omitempty"`
 }
 
 type EnvironmentLookup interface {
@@ -218,6 +312,8 @@ type Project struct {
 	upCount        int
 	listeners      []chan<- ProjectEvent
 	hasListeners   bool
+	slots          chan struct{}
+	slotsOnce      sync.Once
 }
 
 type Service interface {
@@ -232,17 +328,52 @@ type Service interface {
 	Restart() error
 	Log() error
 	Pull() error
+	// Push pushes the image built for the service to its registry.
+	Push() error
 	Kill() error
+	Pause() error
+	Unpause() error
+	// RemoveImage removes the image of the service, if it was built by the
+	// project or if imageType is IMAGE_TYPE_ALL.
+	RemoveImage(imageType ImageType) error
 	Config() *ServiceConfig
 	DependentServices() []ServiceRelationship
 	Containers() ([]Container, error)
 	Scale(count int) error
+	// WaitFor blocks until the service meets the specified condition.
+	WaitFor(condition DependencyCondition) error
+	// Run runs a one-off container of the service and returns its exit code.
+	Run(options RunOptions) (int, error)
+	// Digest returns the digest the image of the service resolves to.
+	Digest() (string, error)
+	// Exec runs a command in the container of the service with the specified
+	// index, starting at 1, and returns its exit code.
+	Exec(index int, options ExecOptions) (int, error)
 }
 
 type Container interface {
 	Id() (string, error)
 	Name() string
 	Port(port string) (string, error)
+	// Wait blocks until the container exits and returns its exit code.
+	Wait() (int, error)
+	// Exec runs a command in the running container and returns its exit code.
+	Exec(options ExecOptions) (int, error)
+	// Inspect returns the current details of the container.
+	Inspect() (*ContainerDetails, error)
+}
+
+// ExecOptions holds the settings of a command run by Container.Exec.
+type ExecOptions struct {
+	Command []string
+	// User overrides the user of the container if not empty.
+	User string
+	// Tty allocates a pseudo-TTY.
+	Tty bool
+	// Stdin, Stdout and Stderr are attached to the command if not nil.
+	// Stderr is unused with a TTY.
+	Stdin          io.Reader
+	Stdout, Stderr io.Writer
 }
 
 type ServiceFactory interface {
@@ -255,11 +386,52 @@ const REL_TYPE_LINK = ServiceRelationshipType("")
 const REL_TYPE_NET_NAMESPACE = ServiceRelationshipType("netns")
 const REL_TYPE_IPC_NAMESPACE = ServiceRelationshipType("ipc")
 const REL_TYPE_VOLUMES_FROM = ServiceRelationshipType("volumesFrom")
+const REL_TYPE_DEPENDS_ON = ServiceRelationshipType("dependsOn")
+
+func (t ServiceRelationshipType) String() string {
+	if t == REL_TYPE_LINK {
+		return "link"
+	}
+	return string(t)
+}
 
 type ServiceRelationship struct {
 	Target, Alias string
 	Type          ServiceRelationshipType
 	Optional      bool
+	// Condition is the condition the target must meet before the service
+	// is started, only set for REL_TYPE_DEPENDS_ON relationships.
+	Condition DependencyCondition
+}
+
+// DependencyCondition is a condition a service must meet before the services
+// that depend on it are started.
+type DependencyCondition string
+
+const (
+	CONDITION_STARTED                = DependencyCondition("started")
+	CONDITION_HEALTHY                = DependencyCondition("healthy")
+	CONDITION_COMPLETED_SUCCESSFULLY = DependencyCondition("completed_successfully")
+	// CONDITION_READY is met when the ready probe of the service succeeds.
+	// Services are always waited on to be ready as part of being started, so
+	// it isn't a depends_on condition.
+	CONDITION_READY = DependencyCondition("ready")
+)
+
+// ParseDependencyCondition parses a depends_on condition. The service_
+// prefix used by Docker Compose (service_healthy) is accepted too.
+func ParseDependencyCondition(value string) (DependencyCondition, error) {
+	if value == "" {
+		return CONDITION_STARTED, nil
+	}
+
+	condition := DependencyCondition(strings.TrimPrefix(value, "service_"))
+	switch condition {
+	case CONDITION_STARTED, CONDITION_HEALTHY, CONDITION_COMPLETED_SUCCESSFULLY:
+		return condition, nil
+	}
+
+	return "", fmt.Errorf("Invalid depends_on condition: %s", value)
 }
 
 func NewServiceRelationship(nameAlias string, relType ServiceRelationshipType) ServiceRelationship {
@@ -0,0 +1,17 @@
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Embedders store and compare the values of the events, so new events go at
+// the end.
+func TestEventValuesAreStable(t *testing.T) {
+	assert.Equal(t, Event(17), SERVICE_PULL)
+	assert.Equal(t, Event(18), SERVICE_KILL_START)
+	assert.Equal(t, Event(23), SERVICE_BUILD)
+	assert.Equal(t, Event(24), PROJECT_DOWN_START)
+	assert.Equal(t, Event(41), PROJECT_BUILD_DONE)
+}
@@ -248,3 +248,64 @@ func (s *MaporSpaceSlice) Slice() []string {
 func NewMaporSpaceSlice(parts []string) MaporSpaceSlice {
 	return MaporSpaceSlice{parts}
 }
+
+type DependsOn struct {
+	parts map[string]DependencyCondition
+}
+
+func (s DependsOn) MarshalYAML() (interface{}, error) {
+	result := map[string]map[string]string{}
+	for name, condition := range s.parts {
+		result[name] = map[string]string{"condition": string(condition)}
+	}
+	return result, nil
+}
+
+func (s *DependsOn) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var sliceType []string
+	err := unmarshal(&sliceType)
+	if err == nil {
+		for _, name := range sliceType {
+			s.set(name, CONDITION_STARTED)
+		}
+		return nil
+	}
+
+	var mapType map[string]struct {
+		Condition string `yaml:"condition"`
+	}
+	err = unmarshal(&mapType)
+	if err != nil {
+		return err
+	}
+
+	for name, value := range mapType {
+		condition, err := ParseDependencyCondition(value.Condition)
+		if err != nil {
+			return err
+		}
+		s.set(name, condition)
+	}
+
+	return nil
+}
+
+func (s *DependsOn) set(name string, condition DependencyCondition) {
+	if s.parts == nil {
+		s.parts = map[string]DependencyCondition{}
+	}
+	s.parts[name] = condition
+}
+
+// Conditions returns the services depended on, with the condition they must
+// meet before the dependent service is started.
+func (s *DependsOn) Conditions() map[string]DependencyCondition {
+	if s == nil {
+		return nil
+	}
+	return s.parts
+}
+
+func NewDependsOn(parts map[string]DependencyCondition) DependsOn {
+	return DependsOn{parts}
+}
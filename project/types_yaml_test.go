@@ -192,3 +192,29 @@ func TestMaporsliceYaml(t *testing.T) {
 	assert.True(t, contains(s2.Foo.parts, "bar=baz"))
 	assert.True(t, contains(s2.Foo.parts, "far=faz"))
 }
+
+type StructDependsOn struct {
+	Foo DependsOn
+}
+
+func TestDependsOnYaml(t *testing.T) {
+	s := StructDependsOn{}
+	err := yaml.Unmarshal([]byte(`{foo: [db, cache]}`), &s)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]DependencyCondition{"db": CONDITION_STARTED, "cache": CONDITION_STARTED}, s.Foo.Conditions())
+
+	s = StructDependsOn{}
+	err = yaml.Unmarshal([]byte(`{foo: {db: {condition: service_healthy}, init: {condition: completed_successfully}}}`), &s)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]DependencyCondition{"db": CONDITION_HEALTHY, "init": CONDITION_COMPLETED_SUCCESSFULLY}, s.Foo.Conditions())
+
+	d, err := yaml.Marshal(&s)
+	assert.Nil(t, err)
+
+	s2 := StructDependsOn{}
+	yaml.Unmarshal(d, &s2)
+	assert.Equal(t, s, s2)
+
+	err = yaml.Unmarshal([]byte(`{foo: {db: {condition: ready}}}`), &StructDependsOn{})
+	assert.NotNil(t, err)
+}
@@ -1,13 +1,17 @@
 package project
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/docker/docker/runconfig"
 )
 
 func DefaultDependentServices(p *Project, s Service) []ServiceRelationship {
-	config := s.Config()
+	return configDependentServices(p, s.Config())
+}
+
+func configDependentServices(p *Project, config *ServiceConfig) []ServiceRelationship {
 	if config == nil {
 		return []ServiceRelationship{}
 	}
@@ -21,8 +25,21 @@ func DefaultDependentServices(p *Project, s Service) []ServiceRelationship {
 		result = append(result, NewServiceRelationship(volumesFrom, REL_TYPE_VOLUMES_FROM))
 	}
 
-	result = appendNs(p, result, s.Config().Net, REL_TYPE_NET_NAMESPACE)
-	result = appendNs(p, result, s.Config().Ipc, REL_TYPE_IPC_NAMESPACE)
+	conditions := config.DependsOn.Conditions()
+	names := []string{}
+	for name := range conditions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rel := NewServiceRelationship(name, REL_TYPE_DEPENDS_ON)
+		rel.Condition = conditions[name]
+		result = append(result, rel)
+	}
+
+	result = appendNs(p, result, config.Net, REL_TYPE_NET_NAMESPACE)
+	result = appendNs(p, result, config.Ipc, REL_TYPE_IPC_NAMESPACE)
 
 	return result
 }
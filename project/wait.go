@@ -0,0 +1,75 @@
+package project
+
+import (
+	"sync"
+)
+
+// ContainerExit reports that a container of a service exited, or that
+// waiting for it failed.
+type ContainerExit struct {
+	Service   string
+	Container string
+	ExitCode  int
+	Err       error
+}
+
+// WaitForExit waits for the containers of the specified services, or of
+// every service if none is specified. An event is sent on the returned
+// channel each time a container exits; the channel is closed once every
+// container exited.
+func (p *Project) WaitForExit(services ...string) (<-chan ContainerExit, error) {
+	names, err := p.SelectServices(services...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		for name := range p.Configs {
+			names = append(names, name)
+		}
+	}
+
+	var containers []Container
+	var owners []string
+
+	for _, name := range names {
+		service, err := p.CreateService(name)
+		if err != nil {
+			return nil, err
+		}
+
+		serviceContainers, err := service.Containers()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, container := range serviceContainers {
+			containers = append(containers, container)
+			owners = append(owners, name)
+		}
+	}
+
+	exits := make(chan ContainerExit, len(containers))
+	wg := sync.WaitGroup{}
+
+	for i, container := range containers {
+		wg.Add(1)
+		go func(service string, container Container) {
+			defer wg.Done()
+			exitCode, err := container.Wait()
+			exits <- ContainerExit{
+				Service:   service,
+				Container: container.Name(),
+				ExitCode:  exitCode,
+				Err:       err,
+			}
+		}(owners[i], container)
+	}
+
+	go func() {
+		wg.Wait()
+		close(exits)
+	}()
+
+	return exits, nil
+}
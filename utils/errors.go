@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// TaskError is an error that happened while operating on a service or
+// one of its containers.
+type TaskError struct {
+	Service   string
+	Container string
+	Err       error
+}
+
+// Error implements error.Error.
+func (e *TaskError) Error() string {
+	buffer := bytes.NewBuffer(nil)
+	if e.Service != "" {
+		fmt.Fprintf(buffer, "service %s: ", e.Service)
+	}
+	if e.Container != "" {
+		fmt.Fprintf(buffer, "container %s: ", e.Container)
+	}
+	buffer.WriteString(e.Err.Error())
+	return buffer.String()
+}
+
+// MultiError collects every error of a set of tasks, typically tasks that
+// were run in parallel.
+type MultiError struct {
+	Errors []*TaskError
+}
+
+// Error implements error.Error.
+func (m *MultiError) Error() string {
+	if len(m.Errors) == 1 {
+		return m.Errors[0].Error()
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	fmt.Fprintf(buffer, "%d errors occurred:", len(m.Errors))
+	for _, err := range m.Errors {
+		buffer.WriteString("\n\t* ")
+		buffer.WriteString(err.Error())
+	}
+	return buffer.String()
+}
+
+// Append adds the specified error to the collection. Nested MultiError are
+// flattened and nil errors are ignored.
+func (m *MultiError) Append(err error) {
+	switch e := err.(type) {
+	case nil:
+	case *MultiError:
+		m.Errors = append(m.Errors, e.Errors...)
+	case *TaskError:
+		m.Errors = append(m.Errors, e)
+	default:
+		m.Errors = append(m.Errors, &TaskError{Err: err})
+	}
+}
+
+// ErrorOrNil returns nil if no error was collected, the MultiError otherwise.
+func (m *MultiError) ErrorOrNil() error {
+	if m == nil || len(m.Errors) == 0 {
+		return nil
+	}
+	return m
+}
+
+// ByService returns the collected errors grouped by service name. Errors
+// that are not related to a service are keyed by the empty string.
+func (m *MultiError) ByService() map[string][]*TaskError {
+	result := map[string][]*TaskError{}
+	for _, err := range m.Errors {
+		result[err.Service] = append(result[err.Service], err)
+	}
+	return result
+}
+
+// Services returns the sorted names of the services that have errors.
+func (m *MultiError) Services() []string {
+	result := []string{}
+	for service := range m.ByService() {
+		if service != "" {
+			result = append(result, service)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ForService annotates the specified error with a service name. Errors that
+// already have a service name are left untouched.
+func ForService(service string, err error) error {
+	return annotate(err, func(e *TaskError) {
+		if e.Service == "" {
+			e.Service = service
+		}
+	})
+}
+
+// ForContainer annotates the specified error with a container name. Errors
+// that already have a container name are left untouched.
+func ForContainer(container string, err error) error {
+	return annotate(err, func(e *TaskError) {
+		if e.Container == "" {
+			e.Container = container
+		}
+	})
+}
+
+func annotate(err error, update func(*TaskError)) error {
+	if err == nil {
+		return nil
+	}
+
+	multi := &MultiError{}
+	multi.Append(err)
+	for _, e := range multi.Errors {
+		update(e)
+	}
+
+	if _, ok := err.(*MultiError); ok {
+		return multi
+	}
+	return multi.Errors[0]
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMultiErrorAppend(t *testing.T) {
+	multi := &MultiError{}
+	if multi.ErrorOrNil() != nil {
+		t.Fatal("Expected no error from an empty MultiError")
+	}
+
+	nested := &MultiError{}
+	nested.Append(fmt.Errorf("first"))
+	nested.Append(fmt.Errorf("second"))
+
+	multi.Append(nil)
+	multi.Append(nested)
+	multi.Append(fmt.Errorf("third"))
+
+	if len(multi.Errors) != 3 {
+		t.Fatalf("Expected 3 flattened errors, got %d: %v", len(multi.Errors), multi.Errors)
+	}
+	if multi.ErrorOrNil() == nil {
+		t.Fatal("Expected an error from a non-empty MultiError")
+	}
+}
+
+func TestMultiErrorAnnotate(t *testing.T) {
+	containerErr := ForContainer("project_web_1", fmt.Errorf("Failed to start"))
+	if containerErr.Error() != "container project_web_1: Failed to start" {
+		t.Fatalf("Unexpected error message: %s", containerErr)
+	}
+
+	multi := &MultiError{}
+	multi.Append(containerErr)
+	multi.Append(ForService("db", fmt.Errorf("Failed to pull")))
+
+	err := ForService("web", multi)
+	byService := err.(*MultiError).ByService()
+	if len(byService["web"]) != 1 || len(byService["db"]) != 1 {
+		t.Fatalf("Unexpected errors by service: %v", byService)
+	}
+	if byService["web"][0].Container != "project_web_1" {
+		t.Fatalf("Expected container name to be kept, got %v", byService["web"][0])
+	}
+
+	services := err.(*MultiError).Services()
+	if len(services) != 2 || services[0] != "db" || services[1] != "web" {
+		t.Fatalf("Expected sorted services [db web], got %v", services)
+	}
+}
+
+func TestInParallelCollectsAllErrors(t *testing.T) {
+	tasks := InParallel{}
+	for i := 0; i < 5; i++ {
+		task := func(index int) func() error {
+			return func() error {
+				return fmt.Errorf("Error with %v", index)
+			}
+		}(i)
+		tasks.Add(task)
+	}
+
+	err := tasks.Wait()
+	multi, ok := err.(*MultiError)
+	if !ok {
+		t.Fatalf("Expected a MultiError, got %v", err)
+	}
+	if len(multi.Errors) != 5 {
+		t.Fatalf("Expected 5 errors, got %d", len(multi.Errors))
+	}
+}
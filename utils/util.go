@@ -10,34 +10,50 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
-// InParallel holds a pool and a waitgroup to execute tasks in parallel and to be able
-// to wait for completion of all tasks.
+// InParallel holds a waitgroup to execute tasks in parallel and to be able
+// to wait for completion of all tasks, and collects the errors of those tasks.
 type InParallel struct {
-	wg   sync.WaitGroup
-	pool sync.Pool
+	wg     sync.WaitGroup
+	lock   sync.Mutex
+	errors MultiError
+	slots  chan struct{}
+}
+
+// NewInParallel creates an InParallel that runs at most limit tasks at the same time.
+// A limit lower than 1 means that tasks are not limited.
+func NewInParallel(limit int) *InParallel {
+	i := &InParallel{}
+	if limit > 0 {
+		i.slots = make(chan struct{}, limit)
+	}
+	return i
 }
 
 // Add adds runs the specified task in parallel and add it to the waitGroup.
+// If a limit is set, the task waits for a free slot before running.
 func (i *InParallel) Add(task func() error) {
 	i.wg.Add(1)
 
 	go func() {
 		defer i.wg.Done()
+		if i.slots != nil {
+			i.slots <- struct{}{}
+			defer func() { <-i.slots }()
+		}
 		err := task()
 		if err != nil {
-			i.pool.Put(err)
+			i.lock.Lock()
+			i.errors.Append(err)
+			i.lock.Unlock()
 		}
 	}()
 }
 
-// Wait waits for all tasks to complete and returns the latests error encountered if any.
+// Wait waits for all tasks to complete and returns a *MultiError holding every
+// error encountered, or nil if all tasks succeeded.
 func (i *InParallel) Wait() error {
 	i.wg.Wait()
-	obj := i.pool.Get()
-	if err, ok := obj.(error); ok {
-		return err
-	}
-	return nil
+	return i.errors.ErrorOrNil()
 }
 
 // ConvertByJSON converts a struct (src) to another one (target) using json marshalling/unmarshalling.
@@ -2,7 +2,9 @@ package utils
 
 import (
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 )
 
 type jsonfrom struct {
@@ -67,6 +69,38 @@ func TestInParallelError(t *testing.T) {
 	}
 }
 
+func TestInParallelLimit(t *testing.T) {
+	size := 10
+	limit := 3
+	var lock sync.Mutex
+	running, max := 0, 0
+	tasks := NewInParallel(limit)
+	for i := 0; i < size; i++ {
+		tasks.Add(func() error {
+			lock.Lock()
+			running++
+			if running > max {
+				max = running
+			}
+			lock.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			lock.Lock()
+			running--
+			lock.Unlock()
+			return nil
+		})
+	}
+	err := tasks.Wait()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if max > limit {
+		t.Fatalf("Expected at most %d tasks running at the same time, got %d", limit, max)
+	}
+}
+
 func TestConvertByJSON(t *testing.T) {
 	valids := []struct {
 		src      jsonfrom